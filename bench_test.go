@@ -0,0 +1,85 @@
+package enflag
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func benchmarkNames(n int) (envs, flags []string) {
+	envs = make([]string, n)
+	flags = make([]string, n)
+	for i := range envs {
+		envs[i] = "BENCH_" + strconv.Itoa(i)
+		flags[i] = "bench-" + strconv.Itoa(i)
+	}
+
+	return envs, flags
+}
+
+func BenchmarkBind(b *testing.B) {
+	envs, flags := benchmarkNames(100)
+	ports := make([]int, 100)
+	hosts := make([]string, 100)
+	timeouts := make([]time.Duration, 100)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reset()
+		for j := 0; j < 100; j += 4 {
+			Var(&ports[j]).Bind(envs[j], flags[j])
+			Var(&hosts[j+1]).WithDefault("localhost").Bind(envs[j+1], flags[j+1])
+			Var(&timeouts[j+2]).BindEnv(envs[j+2])
+			VarFunc(&ports[j+3], strconv.Atoi).BindFlag(flags[j+3])
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	envs, flags := benchmarkNames(100)
+	ports := make([]int, 100)
+	for _, env := range envs {
+		b.Setenv(env, "8080")
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reset()
+		for j := range ports {
+			Var(&ports[j]).Bind(envs[j], flags[j])
+		}
+		Parse()
+	}
+}
+
+func BenchmarkParseSlice(b *testing.B) {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	s := strings.Join(ids, ",")
+	bind := Var(new([]int))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parseSlice(&bind.binding, bind.p, s, "BENCH_IDS", "", strconv.Atoi, false)
+	}
+}
+
+func BenchmarkRepeatedSliceFlag(b *testing.B) {
+	args := make([]string, 0, 2000)
+	for i := 0; i < 1000; i++ {
+		args = append(args, "-bench-id", strconv.Itoa(i))
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reset()
+		var ids []int
+		Var(&ids).BindFlag("bench-id")
+		if err := ParseArgs(args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,12 +1,23 @@
 package enflag
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"math/big"
 	"net"
+	"net/http"
+	"net/mail"
+	"net/netip"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -95,6 +106,31 @@ func TestBind(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "Fixed-size bytes",
+			envs:  []string{"AES_KEY", "AAECAwQFBgcICQoLDA0ODw==", "SHORT_KEY", "AQID"},
+			flags: []string{"hmac-key", strings.Repeat("ab", 32)},
+			f: func(t *testing.T) []func() {
+				var targetAES [16]byte
+				var targetShort [32]byte
+				var targetHMAC [32]byte
+
+				Var(&targetAES).BindEnv("AES_KEY")
+				Var(&targetShort).BindEnv("SHORT_KEY")
+				Var(&targetHMAC).WithDecodeStringFunc(hex.DecodeString).BindFlag("hmac-key")
+
+				wantHMAC := [32]byte{}
+				for i := range wantHMAC {
+					wantHMAC[i] = 0xab
+				}
+
+				return []func(){
+					func() { checkVal(t, [16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, targetAES) },
+					func() { checkVal(t, [32]byte{}, targetShort) },
+					func() { checkVal(t, wantHMAC, targetHMAC) },
+				}
+			},
+		},
 		{
 			name:  "Int slice",
 			envs:  []string{"IDS", "1,3,4"},
@@ -194,6 +230,304 @@ func TestBind(t *testing.T) {
 				return toSlice(func() { checkSlice(t, []float64{1, 3, 4}, target) })
 			},
 		},
+		{
+			name:  "Byte size",
+			envs:  []string{"MEMORY_LIMIT", "512MiB", "BUFFERS", "4k,1.5KB,64"},
+			flags: []string{"upload-limit", "1.5G", "cache-size", "2 TB"},
+			f: func(t *testing.T) []func() {
+				var targetMemory ByteSize
+				var targetBuffers []ByteSize
+				var targetUpload ByteSize
+				var targetCache ByteSize
+
+				VarNamed(&targetMemory).BindEnv("MEMORY_LIMIT")
+				VarNamed(&targetBuffers).BindEnv("BUFFERS")
+				VarNamed(&targetUpload).WithMax(1 << 30).WithDefault(10 << 20).BindFlag("upload-limit")
+				VarNamed(&targetCache).BindFlag("cache-size")
+
+				return []func(){
+					func() { checkVal(t, ByteSize(512<<20), targetMemory) },
+					func() { checkSlice(t, []ByteSize{4000, 1500, 64}, targetBuffers) },
+					func() { checkVal(t, ByteSize(10<<20), targetUpload) },
+					func() { checkVal(t, ByteSize(2e12), targetCache) },
+				}
+			},
+		},
+		{
+			name:  "DSN",
+			envs:  []string{"DATABASE_URL", "postgres://app:secret@db:5432/app", "REPLICAS", "mysql://r1:3306/app,mysql://r2:3306/app"},
+			flags: []string{"cache-dsn", "redis:///0", "audit-dsn", "postgres://audit@db/audit"},
+			f: func(t *testing.T) []func() {
+				var targetDB DSN
+				var targetReplicas []DSN
+				var targetCache DSN
+				var targetAudit DSN
+
+				VarNamed(&targetDB).WithValidate(DSNCredentials).BindEnv("DATABASE_URL")
+				VarNamed(&targetReplicas).BindEnv("REPLICAS")
+				VarNamed(&targetCache).WithDefault("redis://localhost/0").BindFlag("cache-dsn")
+				VarNamed(&targetAudit).WithValidate(DSNCredentials).BindFlag("audit-dsn")
+
+				return []func(){
+					func() { checkVal(t, DSN("postgres://app:secret@db:5432/app"), targetDB) },
+					func() { checkVal(t, "postgres://app:xxxxx@db:5432/app", targetDB.String()) },
+					func() { checkVal(t, "db:5432", targetDB.URL().Host) },
+					func() { checkSlice(t, []DSN{"mysql://r1:3306/app", "mysql://r2:3306/app"}, targetReplicas) },
+					func() { checkVal(t, DSN("redis://localhost/0"), targetCache) },
+					func() { checkVal(t, DSN(""), targetAudit) },
+				}
+			},
+		},
+		{
+			name:  "Host and port",
+			envs:  []string{"LISTEN_ADDR", ":8080", "PEERS", "node1:7000,[::1]:7001"},
+			flags: []string{"upstream", "localhost", "dns", "[::1]:"},
+			f: func(t *testing.T) []func() {
+				var targetListen HostPort
+				var targetPeers []HostPort
+				var targetUpstream HostPort
+				var targetDNS *HostPort
+
+				VarNamed(&targetListen).BindEnv("LISTEN_ADDR")
+				VarNamed(&targetPeers).BindEnv("PEERS")
+				VarNamed(&targetUpstream).WithDefault("localhost:9000").BindFlag("upstream")
+				VarNamed(&targetDNS).BindFlag("dns")
+
+				return []func(){
+					func() { checkVal(t, HostPort(":8080"), targetListen) },
+					func() { checkVal(t, "8080", targetListen.Port()) },
+					func() { checkSlice(t, []HostPort{"node1:7000", "[::1]:7001"}, targetPeers) },
+					func() { checkVal(t, "::1", targetPeers[1].Host()) },
+					func() { checkVal(t, HostPort("localhost:9000"), targetUpstream) },
+					func() { checkVal(t, nil, targetDNS) },
+				}
+			},
+		},
+		{
+			name:  "Headers and query values",
+			envs:  []string{"DEFAULT_HEADERS", "x-tenant=abc&X-Trace=1", "HEADER_LINES", "Accept: a\nAccept: b\nAuthorization: Bearer x=="},
+			flags: []string{"query", "a=1&b=x%20y&a=2", "params", "k=v"},
+			f: func(t *testing.T) []func() {
+				var targetHeaders http.Header
+				var targetLines http.Header
+				var targetQuery url.Values
+				var targetParams map[string][]string
+
+				Var(&targetHeaders).BindEnv("DEFAULT_HEADERS")
+				Var(&targetLines).BindEnv("HEADER_LINES")
+				Var(&targetQuery).BindFlag("query")
+				Var(&targetParams).BindFlag("params")
+
+				return []func(){
+					func() { checkVal(t, "abc", targetHeaders.Get("X-Tenant")) },
+					func() { checkVal(t, "1", targetHeaders.Get("X-Trace")) },
+					func() { checkSlice(t, []string{"a", "b"}, targetLines.Values("Accept")) },
+					func() { checkVal(t, "Bearer x==", targetLines.Get("Authorization")) },
+					func() { checkSlice(t, []string{"1", "2"}, targetQuery["a"]) },
+					func() { checkVal(t, "x y", targetQuery.Get("b")) },
+					func() { checkSlice(t, []string{"v"}, targetParams["k"]) },
+				}
+			},
+		},
+		{
+			name:  "UUID",
+			envs:  []string{"TENANT_ID", "F47AC10B-58CC-4372-A567-0E02B2C3D479", "ADMIN_IDS", "00000000-0000-0000-0000-000000000001,6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+			flags: []string{"request-id", "f47ac10b58cc4372a5670e02b2c3d479", "trace-id", "f47ac10b-58cc-4372-a567-0e02b2c3d4zz"},
+			f: func(t *testing.T) []func() {
+				var targetTenant UUID
+				var targetAdmins []UUID
+				var targetRequest UUID
+				var targetTrace UUID
+
+				def := UUID{15: 1}
+				VarNamed(&targetTenant).BindEnv("TENANT_ID")
+				VarNamed(&targetAdmins).BindEnv("ADMIN_IDS")
+				VarNamed(&targetRequest).WithDefault(def).BindFlag("request-id")
+				VarNamed(&targetTrace).WithDefault(def).BindFlag("trace-id")
+
+				return []func(){
+					func() { checkVal(t, "f47ac10b-58cc-4372-a567-0e02b2c3d479", targetTenant.String()) },
+					func() { checkVal(t, 2, len(targetAdmins)) },
+					func() { checkVal(t, def, targetAdmins[0]) },
+					func() { checkVal(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", targetAdmins[1].String()) },
+					func() { checkVal(t, def, targetRequest) },
+					func() { checkVal(t, def, targetTrace) },
+				}
+			},
+		},
+		{
+			name:  "Semantic version",
+			envs:  []string{"MIN_CLIENT_VERSION", "v1.4.0", "API_VERSION", "2.0.0-rc.1+build.5"},
+			flags: []string{"agent-version", "1.2.9", "plugin-version", "1.02.0"},
+			f: func(t *testing.T) []func() {
+				var targetMinClient SemVer
+				var targetAPI SemVer
+				var targetAgent SemVer
+				var targetPlugin SemVer
+
+				def := SemVer{Major: 1, Minor: 3}
+				VarNamed(&targetMinClient).BindEnv("MIN_CLIENT_VERSION")
+				VarNamed(&targetAPI).BindEnv("API_VERSION")
+				VarNamed(&targetAgent).WithDefault(def).WithMin(def).BindFlag("agent-version")
+				VarNamed(&targetPlugin).BindFlag("plugin-version")
+
+				return []func(){
+					func() { checkVal(t, SemVer{Major: 1, Minor: 4}, targetMinClient) },
+					func() { checkVal(t, "2.0.0-rc.1+build.5", targetAPI.String()) },
+					func() { checkVal(t, def, targetAgent) },
+					func() { checkVal(t, SemVer{}, targetPlugin) },
+				}
+			},
+		},
+		{
+			name:  "Cron expression",
+			envs:  []string{"BACKUP_SCHEDULE", "*/15 9-17 * * MON-FRI", "REPORT_SCHEDULE", "@daily"},
+			flags: []string{"cleanup-schedule", "0 3 1,15 jan-jun/2 0", "sync-schedule", "60 * * * *"},
+			f: func(t *testing.T) []func() {
+				var targetBackup CronExpr
+				var targetReport CronExpr
+				var targetCleanup CronExpr
+				var targetSync CronExpr
+
+				VarNamed(&targetBackup).BindEnv("BACKUP_SCHEDULE")
+				VarNamed(&targetReport).BindEnv("REPORT_SCHEDULE")
+				VarNamed(&targetCleanup).BindFlag("cleanup-schedule")
+				VarNamed(&targetSync).WithDefault("@hourly").BindFlag("sync-schedule")
+
+				return []func(){
+					func() { checkVal(t, CronExpr("*/15 9-17 * * MON-FRI"), targetBackup) },
+					func() { checkVal(t, CronExpr("@daily"), targetReport) },
+					func() { checkVal(t, CronExpr("0 3 1,15 jan-jun/2 0"), targetCleanup) },
+					func() { checkVal(t, CronExpr("@hourly"), targetSync) },
+				}
+			},
+		},
+		{
+			name: "Big numbers",
+			envs: []string{
+				"MAX_SUPPLY", "21000000000000000000000000", "FIELD_PRIME", "0xffffffff00000001",
+				"PI", "3.14159265358979323846264338327950288",
+			},
+			flags: []string{"fee-rate", "0.000000000000000000123", "bad-int", "12a"},
+			f: func(t *testing.T) []func() {
+				var targetSupply *big.Int
+				var targetPrime *big.Int
+				var targetFee *big.Float
+				var targetPi *big.Float
+				var targetBad *big.Int
+
+				def := big.NewInt(7)
+				Var(&targetSupply).BindEnv("MAX_SUPPLY")
+				Var(&targetPrime).BindEnv("FIELD_PRIME")
+				Var(&targetFee).BindFlag("fee-rate")
+				Var(&targetPi).BindEnv("PI")
+				Var(&targetBad).WithDefault(def).BindFlag("bad-int")
+
+				wantSupply, _ := new(big.Int).SetString("21000000000000000000000000", 10)
+
+				return []func(){
+					func() { checkVal(t, 0, wantSupply.Cmp(targetSupply)) },
+					func() { checkVal(t, uint64(0xffffffff00000001), targetPrime.Uint64()) },
+					func() { checkVal(t, "1.23e-19", targetFee.Text('g', 10)) },
+					func() { checkVal(t, "3.14159265358979323846264338327950288", targetPi.Text('f', 35)) },
+					func() { checkVal(t, def, targetBad) },
+				}
+			},
+		},
+		{
+			name: "Numeric widths",
+			envs: []string{
+				"I8", "-128", "I16", "32767", "I32", "-5,6",
+				"U8", "255", "U16", "1,2", "U32", "4294967295",
+				"F32", "0.25,1.5",
+			},
+			flags: []string{"workers", "16"},
+			f: func(t *testing.T) []func() {
+				var targetI8 int8
+				var targetI16 int16
+				var targetI32 []int32
+				var targetU8 uint8
+				var targetU16 []uint16
+				var targetU32 uint32
+				var targetF32 []float32
+				var targetWorkers int32
+
+				Var(&targetI8).BindEnv("I8")
+				Var(&targetI16).BindEnv("I16")
+				Var(&targetI32).BindEnv("I32")
+				Var(&targetU8).BindEnv("U8")
+				Var(&targetU16).BindEnv("U16")
+				Var(&targetU32).BindEnv("U32")
+				Var(&targetF32).BindEnv("F32")
+				Var(&targetWorkers).WithMin(1).WithMax(64).BindFlag("workers")
+
+				return []func(){
+					func() { checkVal(t, int8(-128), targetI8) },
+					func() { checkVal(t, int16(32767), targetI16) },
+					func() { checkSlice(t, []int32{-5, 6}, targetI32) },
+					func() { checkVal(t, uint8(255), targetU8) },
+					func() { checkSlice(t, []uint16{1, 2}, targetU16) },
+					func() { checkVal(t, uint32(4294967295), targetU32) },
+					func() { checkSlice(t, []float32{0.25, 1.5}, targetF32) },
+					func() { checkVal(t, int32(16), targetWorkers) },
+				}
+			},
+		},
+		{
+			name:  "JSON number",
+			envs:  []string{"PRICE", "19.990", "LIMITS", "1,2.5e3,-0"},
+			flags: []string{"rate", "1e", "ratio", " 0.5 "},
+			f: func(t *testing.T) []func() {
+				var targetPrice json.Number
+				var targetLimits []json.Number
+				var targetRate json.Number
+				var targetRatio json.Number
+
+				Var(&targetPrice).BindEnv("PRICE")
+				Var(&targetLimits).BindEnv("LIMITS")
+				Var(&targetRate).WithDefault("1").BindFlag("rate")
+				Var(&targetRatio).BindFlag("ratio")
+
+				return []func(){
+					func() { checkVal(t, json.Number("19.990"), targetPrice) },
+					func() { checkSlice(t, []json.Number{"1", "2.5e3", "-0"}, targetLimits) },
+					func() { checkVal(t, json.Number("1"), targetRate) },
+					func() { checkVal(t, json.Number("0.5"), targetRatio) },
+				}
+			},
+		},
+		{
+			name:  "Pointers",
+			envs:  []string{"OPT_NAME", "api", "OPT_TTL", "5s", "OPT_DEBUG", "true"},
+			flags: []string{"opt-workers", "4", "opt-ratio", "0.5"},
+			f: func(t *testing.T) []func() {
+				var targetName *string
+				var targetTTL *time.Duration
+				var targetDebug *bool
+				var targetWorkers *int
+				var targetRatio *float64
+				var targetPort *uint16
+				var targetLimit *ByteSize
+
+				Var(&targetName).BindEnv("OPT_NAME")
+				Var(&targetTTL).BindEnv("OPT_TTL")
+				Var(&targetDebug).BindEnv("OPT_DEBUG")
+				Var(&targetWorkers).BindFlag("opt-workers")
+				Var(&targetRatio).BindFlag("opt-ratio")
+				Var(&targetPort).Bind("OPT_PORT", "opt-port")
+				VarNamed(&targetLimit).BindEnv("OPT_LIMIT")
+
+				return []func(){
+					func() { checkVal(t, "api", *targetName) },
+					func() { checkVal(t, 5*time.Second, *targetTTL) },
+					func() { checkVal(t, true, *targetDebug) },
+					func() { checkVal(t, 4, *targetWorkers) },
+					func() { checkVal(t, 0.5, *targetRatio) },
+					func() { checkVal(t, nil, targetPort) },
+					func() { checkVal(t, nil, targetLimit) },
+				}
+			},
+		},
 		{
 			name: "Boolean",
 			envs: []string{
@@ -214,6 +548,63 @@ func TestBind(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Extended boolean",
+			envs: []string{
+				"TLS", "Yes",
+				"CACHE", "off",
+				"METRICS", "enabled",
+				"STRICT", "on",
+				"FEATURES", "on,disabled,1",
+			},
+			flags: nil,
+			f: func(t *testing.T) []func() {
+				var targetTLS, targetCache, targetMetrics, targetStrict bool
+				var targetFeatures []bool
+
+				Var(&targetTLS).WithExtendedBool().BindEnv("TLS")
+				Var(&targetCache).WithDefault(true).WithExtendedBool().BindEnv("CACHE")
+				Var(&targetMetrics).WithExtendedBool().BindEnv("METRICS")
+				Var(&targetStrict).BindEnv("STRICT")
+				Var(&targetFeatures).WithExtendedBool().BindEnv("FEATURES")
+
+				return []func(){
+					func() { checkVal(t, true, targetTLS) },
+					func() { checkVal(t, false, targetCache) },
+					func() { checkVal(t, true, targetMetrics) },
+					func() { checkVal(t, false, targetStrict) },
+					func() { checkSlice(t, []bool{true, false, true}, targetFeatures) },
+				}
+			},
+		},
+		{
+			name: "CSV-quoted slice",
+			envs: []string{
+				"NAMES", `"Doe, John",Jane,"say ""hi"""`,
+				"LABELS", `"a=x;y";b=z`,
+				"CSV_INVALID", `"a,b`,
+				"CSV_PIPE", `"a|b"|c`,
+			},
+			flags: nil,
+			f: func(t *testing.T) []func() {
+				var targetNames, targetInvalid, targetPipe, targetMultiSep []string
+				var targetLabels map[string]string
+
+				Var(&targetNames).WithCSV().BindEnv("NAMES")
+				Var(&targetLabels).WithSliceSeparator(";").WithCSV().BindEnv("LABELS")
+				Var(&targetInvalid).WithDefault([]string{"default"}).WithCSV().BindEnv("CSV_INVALID")
+				Var(&targetPipe).WithSliceSeparator("|").WithCSV().BindEnv("CSV_PIPE")
+				Var(&targetMultiSep).WithSliceSeparator("||").WithCSV().BindEnv("CSV_PIPE")
+
+				return []func(){
+					func() { checkSlice(t, []string{"Doe, John", "Jane", `say "hi"`}, targetNames) },
+					func() { checkMap(t, map[string]string{"a": "x;y", "b": "z"}, targetLabels) },
+					func() { checkSlice(t, []string{"default"}, targetInvalid) },
+					func() { checkSlice(t, []string{"a|b", "c"}, targetPipe) },
+					func() { checkSlice(t, nil, targetMultiSep) },
+				}
+			},
+		},
 		{
 			name:  "Bool slice",
 			envs:  []string{"IDS", "1,true,false"},
@@ -296,6 +687,26 @@ func TestBind(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "Pointer slices",
+			envs:  []string{"MIRRORS", "https://a.example.com,https://b.example.com/path"},
+			flags: []string{"holidays", "2025-01-01,2025-12-25"},
+			f: func(t *testing.T) []func() {
+				var targetMirrors []*url.URL
+				var targetHolidays []*time.Time
+
+				Var(&targetMirrors).BindEnv("MIRRORS")
+				Var(&targetHolidays).WithTimeLayout("2006-01-02").BindFlag("holidays")
+
+				return []func(){
+					func() { checkVal(t, 2, len(targetMirrors)) },
+					func() { checkVal(t, "a.example.com", targetMirrors[0].Host) },
+					func() { checkVal(t, "/path", targetMirrors[1].Path) },
+					func() { checkVal(t, 2, len(targetHolidays)) },
+					func() { checkVal(t, time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC), *targetHolidays[1]) },
+				}
+			},
+		},
 		{
 			name: "URL pointer",
 			// for testing parsing from env
@@ -364,6 +775,105 @@ func TestBind(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "CIDR",
+			envs:  []string{"TRUSTED_PROXIES", "10.0.0.0/8,192.168.0.0/16"},
+			flags: []string{"allow", "2001:db8::/32"},
+			f: func(t *testing.T) []func() {
+				var targetProxies []net.IPNet
+				var targetAllow net.IPNet
+				var targetDeny *net.IPNet
+
+				Var(&targetProxies).BindEnv("TRUSTED_PROXIES")
+				Var(&targetAllow).BindFlag("allow")
+				Var(&targetDeny).BindEnv("DENY_RANGE")
+
+				return []func(){
+					func() { checkVal(t, 2, len(targetProxies)) },
+					func() { checkVal(t, "10.0.0.0/8", targetProxies[0].String()) },
+					func() { checkVal(t, "192.168.0.0/16", targetProxies[1].String()) },
+					func() { checkVal(t, true, targetAllow.Contains(net.ParseIP("2001:db8::1"))) },
+					func() { checkVal(t, nil, targetDeny) },
+				}
+			},
+		},
+		{
+			name:  "Mail address",
+			envs:  []string{"ALERT_RECIPIENTS", `"Ops, On-call" <ops@example.com>, dev@example.com`},
+			flags: []string{"sender", "Robot <robot@example.com>"},
+			f: func(t *testing.T) []func() {
+				var targetRecipients []mail.Address
+				var targetSender mail.Address
+				var targetReplyTo *mail.Address
+
+				Var(&targetRecipients).BindEnv("ALERT_RECIPIENTS")
+				Var(&targetSender).BindFlag("sender")
+				Var(&targetReplyTo).BindEnv("REPLY_TO")
+
+				return []func(){
+					func() {
+						checkSlice(t, []mail.Address{
+							{Name: "Ops, On-call", Address: "ops@example.com"},
+							{Address: "dev@example.com"},
+						}, targetRecipients)
+					},
+					func() { checkVal(t, mail.Address{Name: "Robot", Address: "robot@example.com"}, targetSender) },
+					func() { checkVal(t, nil, targetReplyTo) },
+				}
+			},
+		},
+		{
+			name:  "Netip",
+			envs:  []string{"LISTEN", "0.0.0.0:8080", "TRUSTED", "10.0.0.0/8,fd00::/8", "DNS", "::1"},
+			flags: []string{"gateway", "192.168.1.1", "peers", "10.0.0.1:7000,10.0.0.2:7000"},
+			f: func(t *testing.T) []func() {
+				var targetListen netip.AddrPort
+				var targetTrusted []netip.Prefix
+				var targetDNS netip.Addr
+				var targetGateway *netip.Addr
+				var targetPeers []netip.AddrPort
+				var targetProxy *netip.Prefix
+
+				Var(&targetListen).BindEnv("LISTEN")
+				Var(&targetTrusted).BindEnv("TRUSTED")
+				Var(&targetDNS).BindEnv("DNS")
+				Var(&targetGateway).BindFlag("gateway")
+				Var(&targetPeers).BindFlag("peers")
+				Var(&targetProxy).BindEnv("PROXY_RANGE")
+
+				return []func(){
+					func() { checkVal(t, netip.MustParseAddrPort("0.0.0.0:8080"), targetListen) },
+					func() {
+						checkSlice(t, []netip.Prefix{
+							netip.MustParsePrefix("10.0.0.0/8"),
+							netip.MustParsePrefix("fd00::/8"),
+						}, targetTrusted)
+					},
+					func() { checkVal(t, netip.MustParseAddr("::1"), targetDNS) },
+					func() { checkVal(t, netip.MustParseAddr("192.168.1.1"), *targetGateway) },
+					func() { checkVal(t, 2, len(targetPeers)) },
+					func() { checkVal(t, nil, targetProxy) },
+				}
+			},
+		},
+		{
+			name:  "Regexp",
+			envs:  []string{"ROUTE_FILTER", `^/api/v\d+/`},
+			flags: []string{"exclude", "[a-z"},
+			f: func(t *testing.T) []func() {
+				var targetFilter *regexp.Regexp
+				var targetExclude *regexp.Regexp
+				def := regexp.MustCompile(`^$`)
+
+				Var(&targetFilter).BindEnv("ROUTE_FILTER")
+				Var(&targetExclude).WithDefault(def).BindFlag("exclude")
+
+				return []func(){
+					func() { checkVal(t, true, targetFilter.MatchString("/api/v2/users")) },
+					func() { checkVal(t, def, targetExclude) },
+				}
+			},
+		},
 		{
 			name:  "Time",
 			envs:  []string{"DATE_3339", "2025-03-07T12:34:56Z", "OPT_TIME", "2025-03-07T12:34:56Z"},
@@ -413,6 +923,111 @@ func TestBind(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "String map",
+			envs:  []string{"LABELS", "env=prod,team=core"},
+			flags: []string{"weights", "a=1;b=2"},
+			f: func(t *testing.T) []func() {
+				var target map[string]string
+				var targetWeights map[string]int
+
+				Var(&target).WithDefault(map[string]string{"env": "dev"}).BindEnv("LABELS")
+				Var(&targetWeights).WithSliceSeparator(";").BindFlag("weights")
+
+				return []func(){
+					func() { checkMap(t, map[string]string{"env": "prod", "team": "core"}, target) },
+					func() { checkMap(t, map[string]int{"a": 1, "b": 2}, targetWeights) },
+				}
+			},
+		},
+		{
+			name: "Typed maps",
+			envs: []string{
+				"TIMEOUTS", "read=5s,write=10s",
+				"LIMITS", "cpu:0.5,mem:2",
+				"FEATURES", "search=true,beta=0",
+			},
+			f: func(t *testing.T) []func() {
+				var targetTimeouts map[string]time.Duration
+				var targetLimits map[string]float64
+				var targetFeatures map[string]bool
+
+				Var(&targetTimeouts).BindEnv("TIMEOUTS")
+				Var(&targetLimits).WithKeyValueSeparator(":").BindEnv("LIMITS")
+				Var(&targetFeatures).BindEnv("FEATURES")
+
+				return []func(){
+					func() {
+						checkMap(t, map[string]time.Duration{"read": 5 * time.Second, "write": 10 * time.Second}, targetTimeouts)
+					},
+					func() { checkMap(t, map[string]float64{"cpu": 0.5, "mem": 2}, targetLimits) },
+					func() { checkMap(t, map[string]bool{"search": true, "beta": false}, targetFeatures) },
+				}
+			},
+		},
+		{
+			name:  "Location",
+			envs:  []string{"REPORT_TIMEZONE", "Europe/Berlin"},
+			flags: []string{"tz", "Mars/Olympus"},
+			f: func(t *testing.T) []func() {
+				var targetReport *time.Location
+				var targetTZ *time.Location
+
+				Var(&targetReport).BindEnv("REPORT_TIMEZONE")
+				Var(&targetTZ).WithDefault(time.UTC).BindFlag("tz")
+
+				return []func(){
+					func() { checkVal(t, "Europe/Berlin", targetReport.String()) },
+					func() { checkVal(t, time.UTC, targetTZ) },
+				}
+			},
+		},
+		{
+			name:  "File mode",
+			envs:  []string{"UPLOAD_MODE", "0640", "DIR_MODE", "0o750"},
+			flags: []string{"socket-mode", "0999", "log-mode", "1777"},
+			f: func(t *testing.T) []func() {
+				var targetUpload os.FileMode
+				var targetDir os.FileMode
+				var targetSocket os.FileMode
+				var targetLog os.FileMode
+
+				Var(&targetUpload).BindEnv("UPLOAD_MODE")
+				Var(&targetDir).BindEnv("DIR_MODE")
+				Var(&targetSocket).WithDefault(0600).BindFlag("socket-mode")
+				Var(&targetLog).WithDefault(0644).BindFlag("log-mode")
+
+				return []func(){
+					func() { checkVal(t, os.FileMode(0640), targetUpload) },
+					func() { checkVal(t, os.FileMode(0750), targetDir) },
+					func() { checkVal(t, os.FileMode(0600), targetSocket) },
+					func() { checkVal(t, os.FileMode(0644), targetLog) },
+				}
+			},
+		},
+		{
+			name:  "File",
+			envs:  []string{"INPUT_FILE", "go.mod", "CONFIG_FILE", "missing.json"},
+			flags: []string{"output", "-"},
+			f: func(t *testing.T) []func() {
+				var targetInput *os.File
+				var targetConfig *os.File
+				var targetOutput *os.File
+
+				Var(&targetInput).BindEnv("INPUT_FILE")
+				Var(&targetConfig).BindEnv("CONFIG_FILE")
+				Var(&targetOutput).BindFlag("output")
+
+				return []func(){
+					func() {
+						defer targetInput.Close()
+						checkVal(t, "go.mod", targetInput.Name())
+					},
+					func() { checkVal(t, nil, targetConfig) },
+					func() { checkVal(t, os.Stdin, targetOutput) },
+				}
+			},
+		},
 		{
 			name:  "Overwrite default with zero",
 			envs:  []string{"ALERT_THRESHOLD", "0"},
@@ -455,6 +1070,53 @@ func TestBind(t *testing.T) {
 			},
 		},
 
+		{
+			name:  "Custom slice parser",
+			envs:  []string{"LEVELS", "low|high"},
+			flags: []string{"levels", "mid"},
+			f: func(t *testing.T) []func() {
+				var targetEnv []string
+				var targetFlag []string
+				parser := func(s string) (string, error) {
+					return strings.ToUpper(s), nil
+				}
+				VarSliceFunc(&targetEnv, parser).WithSliceSeparator("|").BindEnv("LEVELS")
+				VarSliceFunc(&targetFlag, parser).WithDefault([]string{"LOW"}).BindFlag("levels")
+
+				return []func(){
+					func() { checkSlice(t, []string{"LOW", "HIGH"}, targetEnv) },
+					func() { checkSlice(t, []string{"LOW", "MID"}, targetFlag) },
+				}
+			},
+		},
+
+		{
+			name:  "Nested slices",
+			envs:  []string{"SHARDS", "a1,a2;b1", "MATRIX", "1 2|3 4", "BAD_MATRIX", "1;x"},
+			flags: []string{"ports", "80,443;8080"},
+			f: func(t *testing.T) []func() {
+				var targetShards [][]string
+				var targetMatrix, targetPorts, targetBad [][]int
+				VarNestedSliceFunc(&targetShards, func(s string) (string, error) {
+					return s, nil
+				}).BindEnv("SHARDS")
+				VarNestedSliceFunc(&targetMatrix, strconv.Atoi).
+					WithNestedSeparator("|").
+					WithSliceSeparator(" ").
+					BindEnv("MATRIX")
+				VarNestedSliceFunc(&targetPorts, strconv.Atoi).BindFlag("ports")
+				VarNestedSliceFunc(&targetBad, strconv.Atoi).
+					WithDefault([][]int{{1}}).
+					BindEnv("BAD_MATRIX")
+
+				return []func(){
+					func() { checkVal(t, "[[a1 a2] [b1]]", fmt.Sprint(targetShards)) },
+					func() { checkVal(t, "[[1 2] [3 4]]", fmt.Sprint(targetMatrix)) },
+					func() { checkVal(t, "[[80 443] [8080]]", fmt.Sprint(targetPorts)) },
+					func() { checkVal(t, "[[1]]", fmt.Sprint(targetBad)) },
+				}
+			},
+		},
 		// invalid data
 		{
 			name: "Uint bad env",
@@ -505,6 +1167,18 @@ func TestBind(t *testing.T) {
 			},
 		},
 
+		{
+			name: "Bad map env",
+			envs: []string{"PORTS", "http=80,https,admin=one"},
+
+			f: func(t *testing.T) []func() {
+				var target map[string]uint64
+
+				Var(&target).BindEnv("PORTS")
+
+				return toSlice(func() { checkMap(t, map[string]uint64{"http": 80}, target) })
+			},
+		},
 		{
 			name: "IP bad env",
 			envs: []string{"DNS_IP", "aaa-bbb"},
@@ -531,42 +1205,276 @@ func TestBind(t *testing.T) {
 			},
 		},
 		{
-			name:  "Deprecated Bind",
-			envs:  []string{"PORT", "8080"},
-			flags: []string{"port", "443"},
+			name: "Netip bad env",
+			envs: []string{"LISTEN", "localhost:8080"},
 			f: func(t *testing.T) []func() {
-				var target int
-				Bind(&target, "PORT", "port", 80, "int value")
+				def := netip.MustParseAddrPort("127.0.0.1:80")
+				var target netip.AddrPort
+				Var(&target).WithDefault(def).BindEnv("LISTEN")
 
-				return toSlice(func() { checkVal(t, int(443), target) })
+				return toSlice(func() { checkVal(t, def, target) })
 			},
 		},
 		{
-			name:  "Deprecated BindFunc",
-			envs:  []string{"MY_FORMAT", "aaa"},
-			flags: nil,
+			name: "Mail address bad env",
+			envs: []string{"ALERT_RECIPIENTS", "ops@example.com, not an address"},
 			f: func(t *testing.T) []func() {
-				var target string
-				BindFunc(&target, "MY_FORMAT", "my-format", "a", "int value", func(s string) (string, error) {
-					return s + "-bbb", nil
-				})
+				var target []mail.Address
+				Var(&target).BindEnv("ALERT_RECIPIENTS")
 
-				return toSlice(func() { checkVal(t, "aaa-bbb", target) })
+				return toSlice(func() { checkVal(t, 0, len(target)) })
 			},
 		},
-	}
-
-	for _, c := range cases {
-		t.Run(c.name, func(t *testing.T) {
-			reset()
+		{
+			name: "Byte size bad env",
+			envs: []string{"MEMORY_LIMIT", "1XB", "BUFFERS", "-1", "LIMITS", "9999999PB"},
+			f: func(t *testing.T) []func() {
+				var targetMemory ByteSize
+				var targetBuffers []ByteSize
+				var targetLimits ByteSize
 
-			for _, pair := range toPairs(c.envs) {
-				os.Setenv(pair[0], pair[1])
-			}
+				VarNamed(&targetMemory).WithDefault(1024).BindEnv("MEMORY_LIMIT")
+				VarNamed(&targetBuffers).BindEnv("BUFFERS")
+				VarNamed(&targetLimits).BindEnv("LIMITS")
 
-			checks := c.f(t)
-			for _, pair := range toPairs(c.flags) {
-				flag.Set(pair[0], pair[1])
+				return []func(){
+					func() { checkVal(t, ByteSize(1024), targetMemory) },
+					func() { checkVal(t, 0, len(targetBuffers)) },
+					func() { checkVal(t, ByteSize(0), targetLimits) },
+				}
+			},
+		},
+		{
+			name: "Numeric overflow env",
+			envs: []string{"I8", "128", "U16", "65536", "F32", "1e39"},
+			f: func(t *testing.T) []func() {
+				var targetI8 int8
+				var targetU16 uint16
+				var targetF32 float32
+
+				Var(&targetI8).WithDefault(1).BindEnv("I8")
+				Var(&targetU16).WithDefault(2).BindEnv("U16")
+				Var(&targetF32).WithDefault(3).BindEnv("F32")
+
+				return []func(){
+					func() { checkVal(t, int8(1), targetI8) },
+					func() { checkVal(t, uint16(2), targetU16) },
+					func() { checkVal(t, float32(3), targetF32) },
+				}
+			},
+		},
+		{
+			name: "Custom slice bad env",
+			envs: []string{"PORTS", "80,http,443"},
+			f: func(t *testing.T) []func() {
+				var target []int
+				VarSliceFunc(&target, strconv.Atoi).BindEnv("PORTS")
+
+				return toSlice(func() { checkSlice(t, []int{80, 443}, target) })
+			},
+		},
+		{
+			name:  "Validation",
+			envs:  []string{"PORT", "70000", "HOSTS", "a.int,b.int"},
+			flags: []string{"admin-port", "70001", "retries", "3"},
+			f: func(t *testing.T) []func() {
+				validPort := func(v int) error {
+					if v < 1 || v > 65535 {
+						return errors.New("port must be in range 1-65535")
+					}
+					return nil
+				}
+
+				var targetEnv int
+				var targetFlag int
+				var targetHosts []string
+				var targetRetries int
+
+				Var(&targetEnv).WithDefault(80).WithValidate(validPort).BindEnv("PORT")
+				Var(&targetFlag).WithDefault(8080).WithValidate(validPort).BindFlag("admin-port")
+				Var(&targetHosts).
+					WithDefault([]string{"localhost"}).
+					WithValidate(func(v []string) error {
+						if len(v) > 2 {
+							return errors.New("too many hosts")
+						}
+						return nil
+					}).
+					BindEnv("HOSTS")
+				VarFunc(&targetRetries, strconv.Atoi).
+					WithDefault(1).
+					WithValidate(func(v int) error {
+						if v > 2 {
+							return errors.New("too many retries")
+						}
+						return nil
+					}).
+					BindFlag("retries")
+
+				return []func(){
+					func() { checkVal(t, 80, targetEnv) },
+					func() { checkVal(t, 8080, targetFlag) },
+					func() { checkSlice(t, []string{"localhost"}, targetHosts) },
+					func() { checkVal(t, 1, targetRetries) },
+				}
+			},
+		},
+		{
+			name:  "Allowed values",
+			envs:  []string{"LOG_LEVEL", "warn", "LOG_FORMAT", "xml"},
+			flags: []string{"workers", "3"},
+			f: func(t *testing.T) []func() {
+				var targetLevel string
+				var targetFormat string
+				var targetWorkers int
+
+				Var(&targetLevel).
+					WithDefault("info").
+					WithFlagUsage("log level").
+					WithAllowed("debug", "info", "warn", "error").
+					Bind("LOG_LEVEL", "log-level")
+				Var(&targetFormat).WithDefault("text").WithAllowed("text", "json").Bind("LOG_FORMAT", "log-format")
+				VarFunc(&targetWorkers, strconv.Atoi).WithDefault(1).WithAllowed(1, 2, 4).BindFlag("workers")
+
+				return []func(){
+					func() { checkVal(t, "warn", targetLevel) },
+					func() { checkVal(t, "text", targetFormat) },
+					func() { checkVal(t, 1, targetWorkers) },
+					func() {
+						checkVal(t, "log level (allowed: debug, info, warn, error)", flag.Lookup("log-level").Usage)
+					},
+					func() { checkVal(t, "allowed: text, json", flag.Lookup("log-format").Usage) },
+				}
+			},
+		},
+		{
+			name:  "Allowed slice and pointer values",
+			envs:  []string{"REGIONS", "eu,us", "ZONES", "eu,mars", "REPLICAS", "3"},
+			flags: []string{"shards", "5"},
+			f: func(t *testing.T) []func() {
+				var targetRegions []string
+				var targetZones []string
+				var targetReplicas *int
+				var targetShards *int
+
+				three := 3
+				Var(&targetRegions).WithAllowed([]string{"eu", "us", "asia"}).BindEnv("REGIONS")
+				Var(&targetZones).WithAllowed([]string{"eu", "us"}).BindEnv("ZONES")
+				Var(&targetReplicas).WithAllowed(&three).BindEnv("REPLICAS")
+				Var(&targetShards).WithAllowed(&three).BindFlag("shards")
+
+				return []func(){
+					func() { checkSlice(t, []string{"eu", "us"}, targetRegions) },
+					func() { checkSlice(t, nil, targetZones) },
+					func() { checkVal(t, 3, *targetReplicas) },
+					func() { checkVal(t, nil, targetShards) },
+					func() { checkVal(t, "allowed: 3", flag.Lookup("shards").Usage) },
+				}
+			},
+		},
+		{
+			name:  "Range",
+			envs:  []string{"WORKERS", "64", "TIMEOUT", "90s", "RATIO", "0.5"},
+			flags: []string{"port", "0"},
+			f: func(t *testing.T) []func() {
+				var targetWorkers uint
+				var targetTimeout time.Duration
+				var targetRatio float64
+				var targetPort int
+
+				Var(&targetWorkers).WithDefault(4).WithMin(1).WithMax(32).BindEnv("WORKERS")
+				Var(&targetTimeout).WithDefault(time.Second).WithMax(time.Minute).BindEnv("TIMEOUT")
+				Var(&targetRatio).WithMin(0).WithMax(1).BindEnv("RATIO")
+				Var(&targetPort).WithDefault(80).WithMin(1).BindFlag("port")
+
+				return []func(){
+					func() { checkVal(t, uint(4), targetWorkers) },
+					func() { checkVal(t, time.Second, targetTimeout) },
+					func() { checkVal(t, 0.5, targetRatio) },
+					func() { checkVal(t, 80, targetPort) },
+				}
+			},
+		},
+		{
+			name:  "Non-empty",
+			envs:  []string{"DB_DSN", "", "DB_HOST", "db.int"},
+			flags: []string{"db-user", ""},
+			f: func(t *testing.T) []func() {
+				var targetDSN string
+				var targetHost string
+				var targetUser string
+
+				Var(&targetDSN).WithDefault("postgres://localhost").NonEmpty().BindEnv("DB_DSN")
+				Var(&targetHost).NonEmpty().BindEnv("DB_HOST")
+				Var(&targetUser).NonEmpty().BindFlag("db-user")
+
+				return []func(){
+					func() { checkVal(t, "postgres://localhost", targetDSN) },
+					func() { checkVal(t, "db.int", targetHost) },
+					func() { checkVal(t, "", targetUser) },
+				}
+			},
+		},
+		{
+			name:  "Pattern",
+			envs:  []string{"REGION", "eu-west-1", "ZONES", "a1,b2,C3"},
+			flags: []string{"bucket", "My_Bucket"},
+			f: func(t *testing.T) []func() {
+				var targetRegion string
+				var targetZones []string
+				var targetBucket string
+
+				Var(&targetRegion).WithPattern(regexp.MustCompile(`^[a-z]+-[a-z]+-\d$`)).BindEnv("REGION")
+				Var(&targetZones).WithPattern(regexp.MustCompile(`^[a-z]\d$`)).BindEnv("ZONES")
+				Var(&targetBucket).
+					WithDefault("default-bucket").
+					WithPattern(regexp.MustCompile(`^[a-z0-9-]+$`)).
+					BindFlag("bucket")
+
+				return []func(){
+					func() { checkVal(t, "eu-west-1", targetRegion) },
+					func() { checkSlice(t, []string{}, targetZones) },
+					func() { checkVal(t, "default-bucket", targetBucket) },
+				}
+			},
+		},
+		{
+			name:  "Deprecated Bind",
+			envs:  []string{"PORT", "8080"},
+			flags: []string{"port", "443"},
+			f: func(t *testing.T) []func() {
+				var target int
+				Bind(&target, "PORT", "port", 80, "int value")
+
+				return toSlice(func() { checkVal(t, int(443), target) })
+			},
+		},
+		{
+			name:  "Deprecated BindFunc",
+			envs:  []string{"MY_FORMAT", "aaa"},
+			flags: nil,
+			f: func(t *testing.T) []func() {
+				var target string
+				BindFunc(&target, "MY_FORMAT", "my-format", "a", "int value", func(s string) (string, error) {
+					return s + "-bbb", nil
+				})
+
+				return toSlice(func() { checkVal(t, "aaa-bbb", target) })
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			reset()
+
+			for _, pair := range toPairs(c.envs) {
+				os.Setenv(pair[0], pair[1])
+			}
+
+			checks := c.f(t)
+			for _, pair := range toPairs(c.flags) {
+				flag.Set(pair[0], pair[1])
 			}
 
 			Parse()
@@ -610,6 +1518,927 @@ func TestErrroHandling(t *testing.T) {
 		checkVal(t, 2, exitStatus)
 	})
 
+	t.Run("Validation message", func(t *testing.T) {
+		ErrorHandlerFunc = OnErrorLogAndContinue
+
+		reset()
+		var buf bytes.Buffer
+		flag.CommandLine.SetOutput(&buf)
+
+		var target int
+		os.Setenv("ENV_ERR", "one")
+		os.Setenv("ENV_INVALID", "0")
+		BindVar(&target, "ENV_ERR", "")
+		Var(&target).WithValidate(func(v int) error {
+			return errors.New("must be positive")
+		}).BindEnv("ENV_INVALID")
+
+		os.Setenv("ENV_RANGE", "100")
+		Var(&target).WithMin(1).WithMax(10).BindEnv("ENV_RANGE")
+
+		var host string
+		os.Setenv("ENV_HOST", "local_host")
+		Var(&host).WithPattern(regexp.MustCompile(`^[a-z.]+$`)).BindEnv("ENV_HOST")
+
+		var dsn string
+		Var(&dsn).NonEmpty().Bind("ENV_DSN", "dsn")
+		Parse()
+
+		want := "unable to parse env-variable \"ENV_ERR\" as type int\n" +
+			"unable to parse env-variable \"ENV_INVALID\" as type int: must be positive\n" +
+			"unable to parse env-variable \"ENV_RANGE\" as type int: must be in range [1, 10]\n" +
+			"unable to parse env-variable \"ENV_HOST\" as type string: must match pattern \"^[a-z.]+$\"\n" +
+			"unable to parse env-variable \"ENV_DSN\" as type string: must not be empty\n"
+		checkVal(t, want, buf.String())
+	})
+
+}
+
+func TestRangeUnsupportedType(t *testing.T) {
+	reset()
+
+	defer func() {
+		if r := recover(); r != "enflag: WithMin is not supported for type string" {
+			t.Errorf("unexpected panic %v", r)
+		}
+	}()
+
+	var host string
+	Var(&host).WithMin("a")
+}
+
+func TestPatternUnsupportedType(t *testing.T) {
+	reset()
+
+	defer func() {
+		if r := recover(); r != "enflag: WithPattern is not supported for type int" {
+			t.Errorf("unexpected panic %v", r)
+		}
+	}()
+
+	var port int
+	Var(&port).WithPattern(regexp.MustCompile(`^\d+$`))
+}
+
+func TestExtendedDuration(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+
+	cases := []struct {
+		val  string
+		want time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"1w2d12h", 9*24*time.Hour + 12*time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"-2w", -14 * 24 * time.Hour},
+		{"90m", 90 * time.Minute},
+		{"1d30s500ms", 24*time.Hour + 30*time.Second + 500*time.Millisecond},
+
+		// invalid values keep the default
+		{"d", time.Second},
+		{"1y", time.Second},
+		{"5", time.Second},
+		{"-", time.Second},
+		{"100000000w", time.Second},
+	}
+
+	for _, c := range cases {
+		t.Run(c.val, func(t *testing.T) {
+			reset()
+			os.Setenv("RETENTION", c.val)
+
+			var target time.Duration
+			Var(&target).WithDefault(time.Second).WithExtendedDuration().BindEnv("RETENTION")
+			Parse()
+
+			checkVal(t, c.want, target)
+		})
+	}
+
+	t.Run("Slice and map", func(t *testing.T) {
+		reset()
+		os.Setenv("RETENTIONS", "1d,1w")
+		os.Setenv("RETENTION_BY_TYPE", "logs=7d,metrics=4w")
+
+		var targetSlice []time.Duration
+		var targetMap map[string]time.Duration
+		var targetPlain time.Duration
+		Var(&targetSlice).WithExtendedDuration().BindEnv("RETENTIONS")
+		Var(&targetMap).WithExtendedDuration().BindEnv("RETENTION_BY_TYPE")
+		Var(&targetPlain).WithDefault(time.Second).BindEnv("RETENTION")
+		Parse()
+
+		checkSlice(t, []time.Duration{24 * time.Hour, 7 * 24 * time.Hour}, targetSlice)
+		checkMap(t, map[string]time.Duration{"logs": 7 * 24 * time.Hour, "metrics": 28 * 24 * time.Hour}, targetMap)
+		checkVal(t, time.Second, targetPlain)
+	})
+}
+
+func TestPathValidation(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cert.pem")
+	if err := os.WriteFile(file, []byte("cert"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	os.Setenv("PATH_FILE", file)
+	os.Setenv("PATH_FILE_AS_DIR", file)
+	os.Setenv("PATH_DIR", dir)
+	os.Setenv("PATH_DIR_AS_FILE", dir)
+	os.Setenv("PATH_MISSING_DIR", missing)
+
+	var targetFile, targetFileAsDir, targetDir, targetDirAsFile, targetMissing string
+	Var(&targetFile).WithValidate(ExistingFile).BindEnv("PATH_FILE")
+	Var(&targetFileAsDir).WithValidate(ExistingDir).BindEnv("PATH_FILE_AS_DIR")
+	Var(&targetDir).WithValidate(WritableDir).BindEnv("PATH_DIR")
+	Var(&targetDirAsFile).WithValidate(ExistingFile).BindEnv("PATH_DIR_AS_FILE")
+	Var(&targetMissing).WithValidate(WritableDir).BindEnv("PATH_MISSING_DIR")
+	Parse()
+
+	checkVal(t, file, targetFile)
+	checkVal(t, "", targetFileAsDir)
+	checkVal(t, dir, targetDir)
+	checkVal(t, "", targetDirAsFile)
+	checkVal(t, "", targetMissing)
+
+	entries, _ := os.ReadDir(dir)
+	checkVal(t, 1, len(entries))
+}
+
+func TestFileClose(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	var input, checked *os.File
+	var opened []*os.File
+	Var(&input).
+		WithValidate(func(f *os.File) error {
+			opened = append(opened, f)
+			return nil
+		}).
+		BindFlag("input")
+	Var(&checked).
+		WithValidate(func(f *os.File) error {
+			if filepath.Ext(f.Name()) != ".mod" {
+				return errors.New("not a module file")
+			}
+			return nil
+		}).
+		BindFlag("checked")
+
+	err := flag.CommandLine.Parse([]string{
+		"-input", "go.mod", "-input", "Readme.md",
+		"-checked", "go.mod", "-checked", "Readme.md",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Parse()
+	defer input.Close()
+	defer checked.Close()
+
+	checkVal(t, "Readme.md", input.Name())
+	if _, err := opened[0].Stat(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("the replaced file was not closed: %v", err)
+	}
+	checkVal(t, "go.mod", checked.Name())
+	if _, err := checked.Stat(); err != nil {
+		t.Errorf("the valid file was closed: %v", err)
+	}
+}
+
+func TestCounter(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("COUNTER_VERBOSITY", "2")
+	os.Setenv("COUNTER_RETRIES", "5")
+
+	var verbosity, retries, debug, quiet Counter
+	VarNamed(&verbosity).Bind("COUNTER_VERBOSITY", "v")
+	VarNamed(&retries).Bind("COUNTER_RETRIES", "r")
+	VarNamed(&debug).WithMax(2).BindFlag("d")
+	VarNamed(&quiet).BindFlag("q")
+
+	err := flag.CommandLine.Parse([]string{"-v", "-v", "-v", "-r=1", "-r", "-d", "-d", "-d", "-q=x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Parse()
+
+	checkVal(t, Counter(3), verbosity)
+	checkVal(t, Counter(2), retries)
+	checkVal(t, Counter(2), debug)
+	checkVal(t, Counter(0), quiet)
+}
+
+func TestRepeatedSliceFlags(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("REPEATED_LABELS", "env")
+
+	var labels, hosts, single []string
+	var ports []int
+	Var(&labels).WithDefault([]string{"default"}).Bind("REPEATED_LABELS", "label")
+	// the backing array of the default has room to append in place
+	def := append(make([]string, 0, 4), "localhost")
+	Var(&hosts).WithDefault(def).BindFlag("host")
+	Var(&single).WithDefault([]string{"default"}).Bind("REPEATED_SINGLE", "single")
+	VarSliceFunc(&ports, strconv.Atoi).BindFlag("port")
+
+	err := flag.CommandLine.Parse([]string{
+		"-label", "a,b", "-label", "c",
+		"-host", "h1", "-host", "h2", "-host", "h3",
+		"-port", "80", "-port", "443,8080",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Parse()
+
+	checkSlice(t, []string{"default", "a", "b", "c"}, labels)
+	checkSlice(t, []string{"localhost", "h1", "h2", "h3"}, hosts)
+	checkSlice(t, []string{"localhost", "", "", ""}, def[:4])
+	checkSlice(t, []string{"default"}, single)
+	checkSlice(t, []int{80, 443, 8080}, ports)
+}
+
+func TestRepeatedMapFlags(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("REPEATED_LIMITS", "cpu=1")
+
+	var values map[string]string
+	var limits map[string]int
+	Var(&values).WithDefault(map[string]string{"replicas": "1"}).BindFlag("set")
+	Var(&limits).Bind("REPEATED_LIMITS", "limit")
+
+	err := flag.CommandLine.Parse([]string{
+		"-set", "image.tag=v2", "-set", "env=prod,region=eu", "-set", "env=stage",
+		"-limit", "mem=512",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Parse()
+
+	checkMap(t, map[string]string{"image.tag": "v2", "env": "stage", "region": "eu"}, values)
+	checkMap(t, map[string]int{"mem": 512}, limits)
+}
+
+func TestEnvAliases(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("ALIAS_OLD_HOST", "old")
+	os.Setenv("ALIAS_PLATFORM_HOST", "platform")
+	os.Setenv("ALIAS_PORT", "8080")
+	os.Setenv("ALIAS_OLD_PORT", "80")
+
+	var host string
+	var port int
+	var timeout time.Duration
+
+	hostB := Var(&host).WithEnvAliases("ALIAS_PLATFORM_HOST", "ALIAS_OLD_HOST")
+	hostB.BindEnv("ALIAS_HOST")
+	Var(&port).WithEnvAliases("ALIAS_OLD_PORT").BindEnv("ALIAS_PORT")
+	Var(&timeout).WithDefault(time.Second).WithEnvAliases("ALIAS_OLD_TIMEOUT").BindEnv("ALIAS_TIMEOUT")
+	Parse()
+
+	checkVal(t, "platform", host)
+	checkVal(t, SourceEnv, hostB.Source())
+	checkVal(t, 8080, port)
+	checkVal(t, time.Second, timeout)
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	var global, local []string
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		global = append(global, envName+flagName)
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	os.Setenv("HANDLER_COLOR", "purple")
+	os.Setenv("HANDLER_PORT", "http")
+	os.Args = []string{"cmd", "-handler-width", "wide"}
+
+	var color, port, width int
+	Var(&color).WithDefault(1).WithErrorHandler(OnErrorIgnore).BindEnv("HANDLER_COLOR")
+	Var(&port).WithDefault(80).BindEnv("HANDLER_PORT")
+	VarFunc(&width, strconv.Atoi).
+		WithErrorHandler(func(err error, rawVal string, target any, envName string, flagName string) {
+			local = append(local, flagName)
+		}).
+		BindFlag("handler-width")
+	Parse()
+
+	checkVal(t, 1, color)
+	checkVal(t, 80, port)
+	checkVal(t, 0, width)
+	checkSlice(t, []string{"HANDLER_PORT"}, global)
+	checkSlice(t, []string{"handler-width"}, local)
+}
+
+func TestDeprecated(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	var buf bytes.Buffer
+	flag.CommandLine.SetOutput(&buf)
+
+	os.Setenv("DEPRECATED_ADDR", ":8080")
+
+	var addr string
+	var port int
+	Var(&addr).Bind("LISTEN_ADDR", "listen-addr")
+	Var(&addr).Deprecated("use LISTEN_ADDR instead").BindEnv("DEPRECATED_ADDR")
+	Var(&port).Deprecated("use -listen-addr instead").WithFlagUsage("port").BindFlag("deprecated-port")
+	Var(&port).Deprecated("unused").BindFlag("deprecated-unused")
+
+	err := flag.CommandLine.Parse([]string{"-deprecated-port", "80", "-deprecated-port", "81"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Parse()
+
+	checkVal(t, ":8080", addr)
+	checkVal(t, 81, port)
+
+	want := "flag \"deprecated-port\" is deprecated: use -listen-addr instead\n" +
+		"env-variable \"DEPRECATED_ADDR\" is deprecated: use LISTEN_ADDR instead\n"
+	checkVal(t, want, buf.String())
+
+	usage := flag.CommandLine.Lookup("deprecated-port").Usage
+	checkVal(t, "port (deprecated: use -listen-addr instead)", usage)
+}
+
+func TestFromFile(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	dir := t.TempDir()
+	write := func(name string, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	os.Setenv("FROM_FILE_STR", write("ca.pem", "-----BEGIN CERTIFICATE-----"))
+	os.Setenv("FROM_FILE_BYTES", write("key", "\x00secret"))
+	os.Setenv("FROM_FILE_INT", write("workers", "8"))
+	os.Setenv("FROM_FILE_SLICE", write("hosts", "a,b"))
+	os.Setenv("FROM_FILE_MISSING", filepath.Join(dir, "missing"))
+	os.Setenv("FROM_FILE_HEX", write("token", "736563726574"))
+
+	var str string
+	var key, token []byte
+	var workers int
+	var hosts []string
+	var missing int
+	var custom string
+
+	Var(&str).FromFile().BindEnv("FROM_FILE_STR")
+	Var(&key).FromFile().BindEnv("FROM_FILE_BYTES")
+	Var(&token).WithDecodeStringFunc(hex.DecodeString).FromFile().BindEnv("FROM_FILE_HEX")
+	Var(&workers).FromFile().BindEnv("FROM_FILE_INT")
+	Var(&hosts).FromFile().BindEnv("FROM_FILE_SLICE")
+	Var(&missing).WithDefault(2).FromFile().BindEnv("FROM_FILE_MISSING")
+	VarFunc(&custom, func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	}).FromFile().BindFlag("from-file-custom")
+
+	flag.Set("from-file-custom", write("custom", "abc"))
+	Parse()
+
+	checkVal(t, "-----BEGIN CERTIFICATE-----", str)
+	checkVal(t, "\x00secret", string(key))
+	checkVal(t, "secret", string(token))
+	checkVal(t, 8, workers)
+	checkSlice(t, []string{"a", "b"}, hosts)
+	checkVal(t, 2, missing)
+	checkVal(t, "ABC", custom)
+}
+
+func TestEnvFileSuffix(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	EnvFileSuffix = "_FILE"
+	defer func() { EnvFileSuffix = "" }()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "password")
+	if err := os.WriteFile(file, []byte("qwerty"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("SUFFIX_PASSWORD_FILE", file)
+	os.Setenv("SUFFIX_USER", "admin")
+	os.Setenv("SUFFIX_USER_FILE", file)
+	os.Setenv("SUFFIX_PORT_FILE", filepath.Join(dir, "missing"))
+
+	// the contents of the file are prepared like the values of the variables
+	token := filepath.Join(dir, "token")
+	if err := os.WriteFile(token, []byte(EncryptedPrefix+"c2VjcmV0"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("SUFFIX_TOKEN_FILE", token)
+
+	var password, user, tokenVal string
+	var port int
+
+	passwordB := Var(&password)
+	passwordB.BindEnv("SUFFIX_PASSWORD")
+	Var(&user).BindEnv("SUFFIX_USER")
+	Var(&port).WithDefault(80).BindEnv("SUFFIX_PORT")
+	Var(&tokenVal).
+		WithDecrypt(func(b []byte) ([]byte, error) { return bytes.ToUpper(b), nil }).
+		BindEnv("SUFFIX_TOKEN")
+	Parse()
+
+	checkVal(t, "qwerty", password)
+	checkVal(t, SourceEnv, passwordB.Source())
+	checkVal(t, "admin", user)
+	checkVal(t, 80, port)
+	checkVal(t, "SECRET", tokenVal)
+}
+
+func TestTrimSpace(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	EnvFileSuffix = "_FILE"
+	defer func() { EnvFileSuffix = "" }()
+
+	file := filepath.Join(t.TempDir(), "port")
+	if err := os.WriteFile(file, []byte("5432\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("TRIM_PORT_FILE", file)
+	os.Setenv("TRIM_URL", " http://localhost\r\n")
+	os.Setenv("TRIM_RAW", " raw\n")
+	os.Setenv("TRIM_WORKERS", "4\n")
+
+	var port, workers int
+	var url, raw string
+	Var(&port).WithTrimSpace().BindEnv("TRIM_PORT")
+	Var(&url).WithTrimSpace().BindEnv("TRIM_URL")
+	Var(&raw).BindEnv("TRIM_RAW")
+	Var(&workers).WithDefault(1).BindEnv("TRIM_WORKERS")
+	Parse()
+
+	checkVal(t, 5432, port)
+	checkVal(t, "http://localhost", url)
+	checkVal(t, " raw\n", raw)
+	checkVal(t, 1, workers)
+
+	reset()
+	TrimSpace = true
+	defer func() { TrimSpace = false }()
+
+	Var(&workers).WithDefault(1).BindEnv("TRIM_WORKERS")
+	Parse()
+
+	checkVal(t, 4, workers)
+}
+
+func TestTryParse(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	var port int
+	var host string
+	Var(&port).WithDefault(80).BindFlag("try-port")
+	Var(&host).NonEmpty().BindEnv("TRY_HOST")
+
+	if err := TryParse(); err == nil {
+		t.Fatal("expected an error")
+	} else if mErr := (*MissingError)(nil); !errors.As(err, &mErr) || mErr.Env != "TRY_HOST" {
+		t.Errorf("want MissingError for TRY_HOST, got %v", err)
+	}
+
+	reset()
+	os.Args = []string{"cmd", "-try-port", "http"}
+	Var(&port).WithDefault(80).BindFlag("try-port")
+	err := TryParse()
+
+	var pErr *ParseError
+	if !errors.As(err, &pErr) {
+		t.Fatalf("want ParseError, got %v", err)
+	}
+	checkVal(t, "try-port", pErr.Flag)
+	checkVal(t, "http", pErr.Raw)
+	checkVal(t, "int", pErr.Type)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("want strconv.ErrSyntax, got %v", pErr.Err)
+	}
+	checkVal(t, `unable to parse flag "try-port" as type int: strconv.Atoi: parsing "http": invalid syntax`, err.Error())
+	checkVal(t, 80, port)
+
+	reset()
+	Var(&port).BindFlag("try-port")
+	if err := TryParse(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// the errors of previous calls are not returned again
+	reset()
+	Var(&host).NonEmpty().BindEnv("TRY_HOST")
+	if err := TryParse(); err == nil {
+		t.Fatal("expected an error")
+	}
+	var lazy int
+	Var(&lazy).WithDefault(1).BindEnv("TRY_LAZY")
+	if err := TryParse(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestNonEmptyTypes(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	CollectErrors = true
+	defer func() { CollectErrors = false }()
+	reset()
+
+	os.Setenv("NON_EMPTY_PORT", "8080")
+	os.Setenv("NON_EMPTY_LABELS", "")
+
+	var port, workers int
+	var timeout time.Duration
+	var endpoint *url.URL
+	var labels map[string]string
+	Var(&port).NonEmpty().BindEnv("NON_EMPTY_PORT")
+	Var(&workers).NonEmpty().BindEnv("NON_EMPTY_WORKERS")
+	Var(&timeout).NonEmpty().BindEnv("NON_EMPTY_TIMEOUT")
+	Var(&endpoint).NonEmpty().BindEnv("NON_EMPTY_ENDPOINT")
+	Var(&labels).NonEmpty().BindEnv("NON_EMPTY_LABELS")
+
+	var missing []string
+	for _, err := range TryParse().(Errors) {
+		var mErr *MissingError
+		if errors.As(err, &mErr) {
+			missing = append(missing, mErr.Env)
+		}
+	}
+	checkSlice(t, []string{"NON_EMPTY_WORKERS", "NON_EMPTY_TIMEOUT", "NON_EMPTY_ENDPOINT", "NON_EMPTY_LABELS"}, missing)
+}
+
+func TestSensitiveErrors(t *testing.T) {
+	var raws, msgs []string
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		raws = append(raws, rawVal)
+		msgs = append(msgs, err.Error())
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	os.Setenv("SENSITIVE_PIN", "s3cr3t")
+	os.Setenv("SENSITIVE_TOKEN", "short")
+	os.Setenv("SENSITIVE_KEY_PATH", "/no/such/s3cr3t")
+	os.Setenv("SENSITIVE_LIMIT", "3")
+	os.Setenv("SENSITIVE_PUBLIC", "s3cr3t")
+
+	var pin, limit, public int
+	var token, keyPath string
+	Var(&pin).Sensitive().BindEnv("SENSITIVE_PIN")
+	Var(&token).Sensitive().WithPattern(regexp.MustCompile(`^[a-z]{10}$`)).BindEnv("SENSITIVE_TOKEN")
+	Var(&keyPath).Sensitive().WithValidate(ExistingFile).BindEnv("SENSITIVE_KEY_PATH")
+	Var(&limit).Sensitive().WithMin(5).BindEnv("SENSITIVE_LIMIT")
+	Var(&public).BindEnv("SENSITIVE_PUBLIC")
+	Parse()
+
+	checkSlice(t, []string{"", "", "", "", "s3cr3t"}, raws)
+	checkSlice(t, []string{
+		`unable to parse env-variable "SENSITIVE_PIN" as type int: invalid value (redacted)`,
+		`unable to parse env-variable "SENSITIVE_TOKEN" as type string: must match pattern "^[a-z]{10}$"`,
+		`unable to parse env-variable "SENSITIVE_KEY_PATH" as type string: invalid value (redacted)`,
+		`unable to parse env-variable "SENSITIVE_LIMIT" as type int: must be greater than or equal to 5`,
+		`unable to parse env-variable "SENSITIVE_PUBLIC" as type int: strconv.Atoi: parsing "s3cr3t": invalid syntax`,
+	}, msgs)
+}
+
+func TestCollectErrors(t *testing.T) {
+	var calls int
+	var got error
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		calls++
+		got = err
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	CollectErrors = true
+	defer func() { CollectErrors = false }()
+
+	os.Setenv("COLLECT_PORT", "http")
+	os.Setenv("COLLECT_WORKERS", "0")
+	os.Args = []string{"cmd", "-collect-timeout", "soon"}
+
+	var port, workers int
+	var timeout time.Duration
+	var host string
+	Var(&port).WithDefault(80).BindEnv("COLLECT_PORT")
+	Var(&workers).WithMin(1).BindEnv("COLLECT_WORKERS")
+	Var(&timeout).BindFlag("collect-timeout")
+	Var(&host).NonEmpty().BindEnv("COLLECT_HOST")
+	OnParsed(func() error {
+		return errors.New("TLS cert and key must both be set")
+	})
+
+	checkVal(t, 0, calls)
+	Parse()
+	checkVal(t, 1, calls)
+
+	errs, ok := got.(Errors)
+	if !ok {
+		t.Fatalf("want Errors, got %T", got)
+	}
+	checkVal(t, 5, len(errs))
+	if !errors.Is(got, strconv.ErrSyntax) {
+		t.Error("want strconv.ErrSyntax")
+	}
+	var mErr *MissingError
+	if !errors.As(got, &mErr) || mErr.Env != "COLLECT_HOST" {
+		t.Errorf("want MissingError for COLLECT_HOST, got %v", mErr)
+	}
+	checkVal(t, `invalid configuration: TLS cert and key must both be set`, errs[4].Error())
+	checkVal(t, 80, port)
+
+	var buf bytes.Buffer
+	flag.CommandLine.SetOutput(&buf)
+	OnErrorLogAndContinue(got, "", nil, "", "")
+	want := "unable to parse flag \"collect-timeout\" as type time.Duration\n" +
+		"unable to parse env-variable \"COLLECT_PORT\" as type int\n" +
+		"unable to parse env-variable \"COLLECT_WORKERS\" as type int: must be greater than or equal to 1\n" +
+		"unable to parse env-variable \"COLLECT_HOST\" as type string: must not be empty\n" +
+		"invalid configuration: TLS cert and key must both be set\n"
+	checkVal(t, want, buf.String())
+
+	reset()
+	Var(&port).WithDefault(80).BindEnv("COLLECT_PORT")
+	Var(&workers).WithMin(1).BindEnv("COLLECT_WORKERS")
+	if err := TryParse(); err == nil || len(err.(Errors)) != 2 {
+		t.Errorf("want 2 errors, got %v", err)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	var errFlags []string
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		errFlags = append(errFlags, flagName)
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	fs := flag.NewFlagSet("args", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	FlagSet = fs
+	defer func() { FlagSet = nil }()
+
+	var port int
+	var host string
+	Var(&port).BindFlag("args-port")
+	Var(&host).NonEmpty().BindFlag("args-host")
+
+	if err := ParseArgs([]string{"-args-port", "8080", "-args-host", "", "rest"}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkVal(t, 8080, port)
+	checkSlice(t, []string{"args-host"}, errFlags)
+	checkSlice(t, []string{"rest"}, fs.Args())
+
+	if err := ParseArgs([]string{"-args-unknown"}); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestReParse(t *testing.T) {
+	var errs []string
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		errs = append(errs, envName+flagName)
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	os.Args = []string{"cmd", "-reparse-tag", "a", "-reparse-verbose"}
+	os.Setenv("REPARSE_LATE", "5")
+
+	var tags []string
+	var verbose Counter
+	var host string
+	var hooks int
+	Var(&tags).BindFlag("reparse-tag")
+	VarNamed(&verbose).BindFlag("reparse-verbose")
+	Var(&host).NonEmpty().BindEnv("REPARSE_HOST")
+	OnParsed(func() error { hooks++; return nil })
+	Parse()
+
+	// bindings created lazily, e.g. by a sub-module
+	var late int
+	var lateHost string
+	Var(&late).Bind("REPARSE_LATE", "reparse-late")
+	Var(&lateHost).NonEmpty().BindEnv("REPARSE_LATE_HOST")
+	Parse()
+	Parse()
+
+	checkSlice(t, []string{"a"}, tags)
+	checkVal(t, Counter(1), verbose)
+	checkVal(t, 5, late)
+	checkVal(t, 1, hooks)
+	checkSlice(t, []string{"REPARSE_HOST", "REPARSE_LATE_HOST"}, errs)
+}
+
+func TestDeferredEnv(t *testing.T) {
+	var errs []string
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		errs = append(errs, envName+flagName)
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	os.Setenv("DEFERRED_PORT", "http")
+	defer os.Unsetenv("DEFERRED_PORT")
+	os.Args = []string{"cmd", "-deferred-port", "8080"}
+
+	var port, workers int
+	Var(&port).Bind("DEFERRED_PORT", "deferred-port")
+	Var(&workers).BindEnv("DEFERRED_WORKERS")
+
+	// variables set between binding and parsing are used
+	os.Setenv("DEFERRED_WORKERS", "4")
+	defer os.Unsetenv("DEFERRED_WORKERS")
+	checkVal(t, 0, workers)
+	Parse()
+
+	checkVal(t, 8080, port)
+	checkVal(t, 4, workers)
+	checkSlice(t, nil, errs)
+}
+
+func TestDefaultFromEnv(t *testing.T) {
+	reset()
+
+	os.Setenv("PAAS_PORT", "5000")
+	os.Setenv("FALLBACK_TIMEOUT", "3s")
+	os.Setenv("FALLBACK_HOST", "env.local")
+	defer os.Unsetenv("PAAS_PORT")
+	defer os.Unsetenv("FALLBACK_TIMEOUT")
+	defer os.Unsetenv("FALLBACK_HOST")
+
+	var port, workers int
+	var timeout time.Duration
+	var host string
+	Var(&port).WithDefault(8080).WithDefaultFromEnv("PAAS_PORT").Bind("FALLBACK_PORT", "fallback-port")
+	Var(&workers).WithDefault(4).WithDefaultFromEnv("PAAS_WORKERS").BindEnv("FALLBACK_WORKERS")
+	VarFunc(&timeout, time.ParseDuration).WithDefaultFromEnv("FALLBACK_TIMEOUT").BindFlag("fallback-timeout")
+	Var(&host).WithDefaultFromEnv("PAAS_HOST").BindEnv("FALLBACK_HOST")
+	Parse()
+
+	checkVal(t, 5000, port)
+	checkVal(t, SourceEnv, Source("FALLBACK_PORT"))
+	checkVal(t, 4, workers)
+	checkVal(t, 3*time.Second, timeout)
+	checkVal(t, "env.local", host)
+
+	info, _ := Lookup("fallback-port")
+	checkVal(t, "PAAS_PORT", info.DefaultEnv)
+	checkVal(t, "fallback: $PAAS_PORT", info.FlagUsage)
+}
+
+func TestDefaultFunc(t *testing.T) {
+	reset()
+
+	os.Setenv("DEFAULT_FUNC_HOST", "db.local")
+	defer os.Unsetenv("DEFAULT_FUNC_HOST")
+	os.Args = []string{"cmd", "-default-func-workers", "2"}
+
+	var calls int
+	hostname := func() string {
+		calls++
+		return "node-1"
+	}
+
+	var host, node string
+	var workers int
+	var id []byte
+	Var(&host).WithDefaultFunc(hostname).BindEnv("DEFAULT_FUNC_HOST")
+	Var(&node).WithDefault("localhost").WithDefaultFunc(hostname).BindEnv("DEFAULT_FUNC_NODE")
+	Var(&workers).WithDefaultFunc(func() int { return 8 }).Bind("DEFAULT_FUNC_WORKERS", "default-func-workers")
+	VarFunc(&id, func(s string) ([]byte, error) { return []byte(s), nil }).
+		WithDefaultFunc(func() []byte { return []byte("generated") }).
+		BindEnv("DEFAULT_FUNC_ID")
+
+	checkVal(t, 0, calls)
+	checkVal(t, "localhost", node)
+	Parse()
+
+	checkVal(t, 1, calls)
+	checkVal(t, "db.local", host)
+	checkVal(t, "node-1", node)
+	checkVal(t, SourceDefault, Source("DEFAULT_FUNC_NODE"))
+	checkVal(t, 2, workers)
+	checkVal(t, "generated", string(id))
+}
+
+func TestOnParsed(t *testing.T) {
+	ErrorHandlerFunc = OnErrorLogAndContinue
+	reset()
+
+	var buf bytes.Buffer
+	flag.CommandLine.SetOutput(&buf)
+
+	os.Setenv("ON_PARSED_CERT", "cert.pem")
+
+	var cert, key string
+	Var(&cert).BindEnv("ON_PARSED_CERT")
+	Var(&key).BindEnv("ON_PARSED_KEY")
+
+	var called []string
+	OnParsed(func() error {
+		called = append(called, "tls")
+		if (cert == "") != (key == "") {
+			return errors.New("TLS cert and key must both be set")
+		}
+		return nil
+	})
+	OnParsed(func() error {
+		called = append(called, "ok")
+		return nil
+	})
+	Parse()
+
+	checkSlice(t, []string{"tls", "ok"}, called)
+	checkVal(t, "invalid configuration: TLS cert and key must both be set\n", buf.String())
+}
+
+func TestEnvOnly(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	EnvOnly = true
+	defer func() { EnvOnly = false }()
+
+	os.Args = []string{"cmd", "-unknown-framework-flag"}
+	os.Setenv("ENV_ONLY_PORT", "8080")
+
+	var port int
+	var host string
+	Var(&port).Bind("ENV_ONLY_PORT", "env-only-port")
+	Var(&host).WithDefault("localhost").BindFlag("env-only-host")
+	Version("1.0.0")
+	Parse()
+
+	checkVal(t, 8080, port)
+	checkVal(t, "localhost", host)
+	checkVal(t, false, flag.CommandLine.Parsed())
+
+	count := 0
+	flag.CommandLine.VisitAll(func(*flag.Flag) { count++ })
+	checkVal(t, 0, count)
+
+	info, _ := Lookup("ENV_ONLY_PORT")
+	checkVal(t, "", info.FlagName)
+}
+
+func TestFlagSet(t *testing.T) {
+	ErrorHandlerFunc = OnErrorLogAndContinue
+	reset()
+
+	fs := flag.NewFlagSet("custom", flag.ContinueOnError)
+	FlagSet = fs
+	defer func() { FlagSet = nil }()
+
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+
+	var port int
+	var host string
+	Var(&port).WithDefault(80).BindFlag("fs-port")
+	Var(&host).NonEmpty().BindFlag("fs-host")
+
+	if flag.CommandLine.Lookup("fs-port") != nil {
+		t.Fatal("flag is registered in flag.CommandLine")
+	}
+
+	// emulate a third-party package parsing the flag set, e.g. pflag
+	if err := fs.Parse([]string{"-fs-port", "8080"}); err != nil {
+		t.Fatal(err)
+	}
+	Parse()
+
+	checkVal(t, 8080, port)
+	checkVal(t, "unable to parse flag \"fs-host\" as type string: must not be empty\n", buf.String())
 }
 
 func checkVal[A comparable](t *testing.T, want A, got A) {
@@ -636,7 +2465,24 @@ func checkSlice[A comparable](t *testing.T, want []A, got []A) {
 	}
 }
 
+func checkMap[A comparable](t *testing.T, want map[string]A, got map[string]A) {
+	t.Helper()
+
+	if len(want) != len(got) {
+		t.Errorf("expected %v, got %v", want, got)
+		return
+	}
+
+	for k, v := range want {
+		if gotV, ok := got[k]; !ok || gotV != v {
+			t.Errorf("want %v, got %v, mismatch at key %q", want, got, k)
+			return
+		}
+	}
+}
+
 func reset() {
+	Reset()
 	os.Args = []string{"cmd"}
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 }
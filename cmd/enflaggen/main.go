@@ -0,0 +1,337 @@
+/*
+Enflaggen generates enflag bindings for the fields of a configuration struct,
+for struct ergonomics without runtime reflection.
+
+Fields are annotated with struct tags:
+
+	//go:generate go run github.com/atelpis/enflag/cmd/enflaggen -type Config
+	type Config struct {
+	    Port    int           `env:"PORT" flag:"port" default:"8080" usage:"port to listen on"`
+	    Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+	    Token   string        `env:"API_TOKEN" enflag:"sensitive,nonempty"`
+	}
+
+The generated file, config_enflag.go by default, declares a method binding
+the fields of the struct:
+
+	func (c *Config) Bind() {
+	    enflag.Var(&c.Port).WithDefault(8080).WithFlagUsage("port to listen on").Bind("PORT", "port")
+	    ...
+	}
+
+Fields without env and flag tags are skipped, env:"-" means that the field
+has no environment variable.
+Defaults are supported for strings, booleans, numbers, durations and string
+slices. The enflag tag accepts the sensitive, nonempty and fromfile options.
+
+Usage:
+
+	enflaggen -type Config [-output file] [-method Bind] [dir]
+*/
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the configuration struct, required")
+	output := flag.String("output", "", "output file name, <type>_enflag.go by default")
+	method := flag.String("method", "Bind", "name of the generated method")
+	flag.Parse()
+
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	if *output == "" {
+		*output = strings.ToLower(*typeName) + "_enflag.go"
+	}
+
+	src, err := generateDir(dir, *typeName, *method, *output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "enflaggen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "enflaggen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generateDir parses the Go files of the directory, except tests
+// and the output file, and generates the bindings of the struct.
+func generateDir(dir string, typeName string, method string, output string) ([]byte, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	return generate(files, typeName, method)
+}
+
+// generate returns the formatted source of the method binding
+// the fields of the struct declared in one of the files.
+func generate(files []*ast.File, typeName string, method string) ([]byte, error) {
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != typeName {
+					continue
+				}
+
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					return nil, fmt.Errorf("type %s is not a struct", typeName)
+				}
+
+				return generateStruct(f.Name.Name, typeName, method, st)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("type %s not found", typeName)
+}
+
+func generateStruct(pkg string, typeName string, method string, st *ast.StructType) ([]byte, error) {
+	var body bytes.Buffer
+	imports := map[string]bool{"github.com/atelpis/enflag": true}
+
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+
+		tagVal, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return nil, err
+		}
+		tag := reflect.StructTag(tagVal)
+
+		env, flagName := tag.Get("env"), tag.Get("flag")
+		if env == "-" {
+			env = ""
+		}
+		if env == "" && flagName == "" {
+			continue
+		}
+
+		typ := types.ExprString(field.Type)
+		for _, name := range field.Names {
+			line, err := bindingLine(name.Name, typ, env, flagName, tag, imports)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", name.Name, err)
+			}
+			body.WriteString(line)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by enflaggen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	buf.WriteString("import (\n")
+	for _, path := range paths {
+		fmt.Fprintf(&buf, "%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+
+	fmt.Fprintf(&buf, "// %s binds the fields of c to their environment variables and flags.\n", method)
+	fmt.Fprintf(&buf, "func (c *%s) %s() {\n", typeName, method)
+	buf.Write(body.Bytes())
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// constructor returns the name of the function creating the binding
+// of the given type: the named types of enflag and slog.Level have
+// constructors of their own.
+func constructor(typ string) string {
+	elem := strings.TrimLeft(typ, "*[]")
+	switch {
+	case strings.HasPrefix(elem, "enflag."):
+		return "VarNamed"
+	case elem == "slog.Level":
+		return "VarLevel"
+	}
+	return "Var"
+}
+
+func bindingLine(
+	field string,
+	typ string,
+	env string,
+	flagName string,
+	tag reflect.StructTag,
+	imports map[string]bool,
+) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "enflag.%s(&c.%s)", constructor(typ), field)
+
+	if def, ok := tag.Lookup("default"); ok {
+		lit, err := defaultLiteral(typ, def)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(lit, "time.") {
+			imports["time"] = true
+		}
+		fmt.Fprintf(&b, ".WithDefault(%s)", lit)
+	}
+
+	if usage := tag.Get("usage"); usage != "" {
+		fmt.Fprintf(&b, ".WithFlagUsage(%q)", usage)
+	}
+
+	if opts := tag.Get("enflag"); opts != "" {
+		for _, opt := range strings.Split(opts, ",") {
+			switch strings.TrimSpace(opt) {
+			case "sensitive":
+				b.WriteString(".Sensitive()")
+			case "nonempty":
+				b.WriteString(".NonEmpty()")
+			case "fromfile":
+				b.WriteString(".FromFile()")
+			default:
+				return "", fmt.Errorf("unknown option %q", opt)
+			}
+		}
+	}
+
+	fmt.Fprintf(&b, ".Bind(%q, %q)\n", env, flagName)
+	return b.String(), nil
+}
+
+// bitSize returns the size of a numeric type like "int8" for strconv,
+// which is 0 for int and uint.
+func bitSize(typ string, kind string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(typ, kind))
+	return n
+}
+
+// defaultLiteral returns the Go literal of the default value.
+func defaultLiteral(typ string, def string) (string, error) {
+	switch typ {
+	case "string":
+		return strconv.Quote(def), nil
+
+	case "bool":
+		v, err := strconv.ParseBool(def)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(v), nil
+
+	case "int", "int8", "int16", "int32", "int64":
+		if _, err := strconv.ParseInt(def, 0, bitSize(typ, "int")); err != nil {
+			return "", err
+		}
+		return def, nil
+
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		if _, err := strconv.ParseUint(def, 0, bitSize(typ, "uint")); err != nil {
+			return "", err
+		}
+		return def, nil
+
+	case "float32", "float64":
+		if _, err := strconv.ParseFloat(def, bitSize(typ, "float")); err != nil {
+			return "", err
+		}
+		return def, nil
+
+	case "time.Duration":
+		d, err := time.ParseDuration(def)
+		if err != nil {
+			return "", err
+		}
+		return durationLiteral(d), nil
+
+	case "[]string":
+		if def == "" {
+			return "[]string{}", nil
+		}
+		items := strings.Split(def, ",")
+		for i := range items {
+			items[i] = strconv.Quote(items[i])
+		}
+		return "[]string{" + strings.Join(items, ", ") + "}", nil
+	}
+
+	return "", errors.New("default values are not supported for type " + typ)
+}
+
+// durationLiteral returns the duration as a multiple of the largest
+// unit it is divisible by, e.g. 90 * time.Second.
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+
+	if d == 0 {
+		return "0"
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+
+	return fmt.Sprintf("%d * time.Nanosecond", d)
+}
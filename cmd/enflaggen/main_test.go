@@ -0,0 +1,113 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const configSrc = `package config
+
+import "time"
+
+type Config struct {
+	Host    string        ` + "`env:\"HOST\" flag:\"host\" default:\"localhost\" usage:\"listen host\"`" + `
+	Port    int           ` + "`env:\"PORT\" flag:\"port\" default:\"8080\"`" + `
+	Timeout time.Duration ` + "`env:\"TIMEOUT\" default:\"90s\"`" + `
+	Tags    []string      ` + "`flag:\"tag\" default:\"a,b\"`" + `
+	Token   string        ` + "`env:\"API_TOKEN\" enflag:\"sensitive,nonempty\"`" + `
+	Debug   bool          ` + "`env:\"-\" flag:\"debug\"`" + `
+	Limit   enflag.ByteSize ` + "`env:\"MEMORY_LIMIT\"`" + `
+	Secret  string        ` + "`env:\"-\"`" + `
+	cache   map[string]string
+}
+`
+
+func parseSrc(t *testing.T, src string) []*ast.File {
+	t.Helper()
+
+	f, err := parser.ParseFile(token.NewFileSet(), "config.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return []*ast.File{f}
+}
+
+func TestGenerate(t *testing.T) {
+	res, err := generate(parseSrc(t, configSrc), "Config", "Bind")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `// Code generated by enflaggen; DO NOT EDIT.
+
+package config
+
+import (
+	"github.com/atelpis/enflag"
+	"time"
+)
+
+// Bind binds the fields of c to their environment variables and flags.
+func (c *Config) Bind() {
+	enflag.Var(&c.Host).WithDefault("localhost").WithFlagUsage("listen host").Bind("HOST", "host")
+	enflag.Var(&c.Port).WithDefault(8080).Bind("PORT", "port")
+	enflag.Var(&c.Timeout).WithDefault(90*time.Second).Bind("TIMEOUT", "")
+	enflag.Var(&c.Tags).WithDefault([]string{"a", "b"}).Bind("", "tag")
+	enflag.Var(&c.Token).Sensitive().NonEmpty().Bind("API_TOKEN", "")
+	enflag.Var(&c.Debug).Bind("", "debug")
+	enflag.VarNamed(&c.Limit).Bind("MEMORY_LIMIT", "")
+}
+`
+	if string(res) != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, res)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		typ  string
+		want string
+	}{
+		{"package p\ntype C struct{}", "Config", "type Config not found"},
+		{"package p\ntype Config int", "Config", "type Config is not a struct"},
+		{"package p\ntype Config struct{ P int `env:\"P\" default:\"x\"` }", "Config", "field P: strconv.ParseInt"},
+		{"package p\ntype Config struct{ P int8 `env:\"P\" default:\"300\"` }", "Config", "value out of range"},
+		{"package p\ntype Config struct{ P uint16 `env:\"P\" default:\"70000\"` }", "Config", "value out of range"},
+		{"package p\ntype Config struct{ F float32 `env:\"F\" default:\"1e39\"` }", "Config", "value out of range"},
+		{"package p\ntype Config struct{ U url.URL `env:\"U\" default:\"x\"` }", "Config", "not supported for type url.URL"},
+		{"package p\ntype Config struct{ S string `env:\"S\" enflag:\"secret\"` }", "Config", `unknown option "secret"`},
+	}
+
+	for _, tt := range tests {
+		_, err := generate(parseSrc(t, tt.src), tt.typ, "Bind")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("want error containing %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestGenerateDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(configSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// a stale output file must not break the generation
+	if err := os.WriteFile(filepath.Join(dir, "config_enflag.go"), []byte("broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := generateDir(dir, "Config", "Load", "config_enflag.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(res), "func (c *Config) Load() {") {
+		t.Errorf("want Load method, got %s", res)
+	}
+}
@@ -0,0 +1,41 @@
+package enflag
+
+import "flag"
+
+// GoFlagSetAdder is a flag set accepting the flags of a standard library
+// flag set, e.g. *pflag.FlagSet of spf13/pflag, which is returned by
+// the Flags method of a spf13/cobra command.
+type GoFlagSetAdder interface {
+	AddGoFlagSet(fs *flag.FlagSet)
+}
+
+// AttachToCommand adds the flags of the bindings to the flag set of a command
+// and returns the function which completes the parsing once the command has
+// parsed its flags, e.g. in the PreRunE function of a cobra command:
+//
+//	enflag.FlagSet = flag.NewFlagSet(cmd.Name(), flag.ContinueOnError)
+//	enflag.Var(&conf.DBHost).Bind("DB_HOST", "db-host")
+//
+//	parse := enflag.AttachToCommand(cmd.Flags())
+//	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+//		return parse()
+//	}
+//
+// The bindings with flags must be created before AttachToCommand is called.
+// The returned function reads the other sources like TryParse and returns
+// its error.
+func AttachToCommand(flags GoFlagSetAdder) (parse func() error) {
+	flags.AddGoFlagSet(flagSet())
+
+	return func() error {
+		// the values of the flags are set by the command,
+		// so the flag set is only marked as parsed
+		if !EnvOnly && !flagSet().Parsed() {
+			if err := parseFlags(nil); err != nil {
+				return err
+			}
+		}
+
+		return TryParse()
+	}
+}
@@ -0,0 +1,51 @@
+package enflag
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"testing"
+)
+
+// fakeCommandFlags mimics pflag: the added flags are set directly.
+type fakeCommandFlags struct {
+	added []*flag.FlagSet
+}
+
+func (f *fakeCommandFlags) AddGoFlagSet(fs *flag.FlagSet) {
+	f.added = append(f.added, fs)
+}
+
+func TestAttachToCommand(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	FlagSet = flag.NewFlagSet("serve", flag.ContinueOnError)
+	os.Args = []string{"cmd", "serve", "--cmd-port=8080"}
+	os.Setenv("CMD_HOST", "db.int")
+	defer os.Unsetenv("CMD_HOST")
+
+	var port int
+	var host, user string
+	Var(&port).BindFlag("cmd-port")
+	Var(&host).Bind("CMD_HOST", "cmd-host")
+	Var(&user).NonEmpty().BindEnv("CMD_USER")
+
+	cmd := &fakeCommandFlags{}
+	parse := AttachToCommand(cmd)
+	if len(cmd.added) != 1 || cmd.added[0] != FlagSet {
+		t.Fatal("the flag set was not added to the command")
+	}
+
+	if err := cmd.added[0].Lookup("cmd-port").Value.Set("8080"); err != nil {
+		t.Fatal(err)
+	}
+	err := parse()
+
+	checkVal(t, 8080, port)
+	checkVal(t, "db.int", host)
+	var mErr *MissingError
+	if !errors.As(err, &mErr) || mErr.Env != "CMD_USER" {
+		t.Errorf("want MissingError for CMD_USER, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package enflag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// GenerateCompletion writes a shell completion script for the flags of all
+// registered bindings to w. The shell must be one of "bash", "zsh" or "fish",
+// and program is the name of the executable to complete.
+//
+// Values of bindings restricted with WithAllowed are completed as well.
+func GenerateCompletion(w io.Writer, shell string, program string) error {
+	var bindings []*binding
+	for _, b := range registered() {
+		if b.flagName != "" {
+			bindings = append(bindings, b)
+		}
+	}
+
+	var buf bytes.Buffer
+	switch shell {
+	case "bash":
+		bashCompletion(&buf, program, bindings)
+	case "zsh":
+		zshCompletion(&buf, program, bindings)
+	case "fish":
+		fishCompletion(&buf, program, bindings)
+	default:
+		return fmt.Errorf("unsupported shell %q", shell)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func bashCompletion(buf *bytes.Buffer, program string, bindings []*binding) {
+	fn := "_" + nonIdentChars.ReplaceAllString(program, "_") + "_completions"
+
+	fmt.Fprintf(buf, "%s() {\n", fn)
+	buf.WriteString("    local cur prev\n")
+	buf.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	buf.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	buf.WriteString("\n")
+	buf.WriteString("    case \"$prev\" in\n")
+	for _, b := range bindings {
+		if len(b.allowed) == 0 || isBoolFlag(b) {
+			continue
+		}
+
+		fmt.Fprintf(buf, "        -%s|--%s)\n", b.flagName, b.flagName)
+		fmt.Fprintf(buf, "            COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(b.allowed, " ")))
+		buf.WriteString("            return 0\n")
+		buf.WriteString("            ;;\n")
+	}
+	buf.WriteString("    esac\n")
+	buf.WriteString("\n")
+
+	flags := make([]string, len(bindings))
+	for i, b := range bindings {
+		flags[i] = "-" + b.flagName
+	}
+	fmt.Fprintf(buf, "    COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(flags, " ")))
+	buf.WriteString("}\n")
+	fmt.Fprintf(buf, "complete -F %s %s\n", fn, program)
+}
+
+func zshCompletion(buf *bytes.Buffer, program string, bindings []*binding) {
+	fmt.Fprintf(buf, "#compdef %s\n\n", program)
+	buf.WriteString("_arguments")
+	for _, b := range bindings {
+		values := ""
+		if len(b.allowed) > 0 {
+			values = "(" + strings.Join(b.allowed, " ") + ")"
+		}
+
+		desc := strings.NewReplacer("[", "\\[", "]", "\\]", ":", "\\:").Replace(b.flagUsage)
+		spec := fmt.Sprintf("-%s[%s]", b.flagName, desc)
+		if !isBoolFlag(b) {
+			spec += ":value:" + values
+		}
+		fmt.Fprintf(buf, " \\\n    %s", shellQuote(spec))
+	}
+	buf.WriteString("\n")
+}
+
+func fishCompletion(buf *bytes.Buffer, program string, bindings []*binding) {
+	for _, b := range bindings {
+		fmt.Fprintf(buf, "complete -c %s -o %s", program, b.flagName)
+		if !isBoolFlag(b) {
+			buf.WriteString(" -r")
+			if len(b.allowed) > 0 {
+				fmt.Fprintf(buf, " -f -a %s", shellQuote(strings.Join(b.allowed, " ")))
+			}
+		}
+		if b.flagUsage != "" {
+			fmt.Fprintf(buf, " -d %s", shellQuote(b.flagUsage))
+		}
+		buf.WriteString("\n")
+	}
+}
+
+// isBoolFlag reports whether the flag of the binding takes no value,
+// like the flag of a Counter binding. The flags of bool bindings
+// take a value, e.g. -verbose=true.
+func isBoolFlag(b *binding) bool {
+	f := flagSet().Lookup(b.flagName)
+	if f == nil {
+		return false
+	}
+
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
+
+var nonIdentChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// shellQuote quotes s for POSIX-compatible shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
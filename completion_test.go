@@ -0,0 +1,84 @@
+package enflag
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGenerateCompletion(t *testing.T) {
+	reset()
+
+	var port int
+	var level string
+	var env string
+	var verbose bool
+	var v Counter
+	// bool bindings take a value, unlike Counter
+
+	Var(&port).WithFlagUsage("port to listen on").Bind("PORT", "port")
+	Var(&level).WithAllowed("debug", "info").WithFlagUsage("log level").BindFlag("log-level")
+	Var(&env).BindEnv("ENV")
+	Var(&verbose).WithFlagUsage("verbose output").BindFlag("verbose")
+	VarNamed(&v).BindFlag("v")
+
+	cases := []struct {
+		shell string
+		want  string
+	}{
+		{
+			shell: "bash",
+			want: `_my_app_completions() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    case "$prev" in
+        -log-level|--log-level)
+            COMPREPLY=($(compgen -W 'debug info' -- "$cur"))
+            return 0
+            ;;
+    esac
+
+    COMPREPLY=($(compgen -W '-port -log-level -verbose -v' -- "$cur"))
+}
+complete -F _my_app_completions my-app
+`,
+		},
+		{
+			shell: "zsh",
+			want: `#compdef my-app
+
+_arguments \
+    '-port[port to listen on]:value:' \
+    '-log-level[log level]:value:(debug info)' \
+    '-verbose[verbose output]:value:' \
+    '-v[]'
+`,
+		},
+		{
+			shell: "fish",
+			want: `complete -c my-app -o port -r -d 'port to listen on'
+complete -c my-app -o log-level -r -f -a 'debug info' -d 'log level'
+complete -c my-app -o verbose -r -d 'verbose output'
+complete -c my-app -o v
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := GenerateCompletion(&buf, c.shell, "my-app"); err != nil {
+				t.Fatal(err)
+			}
+
+			checkVal(t, c.want, buf.String())
+		})
+	}
+
+	t.Run("Unsupported shell", func(t *testing.T) {
+		if err := GenerateCompletion(&bytes.Buffer{}, "tcsh", "my-app"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package enflag
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Debug enables tracing of the resolution of every binding: the sources
+// which are checked, the values found in them, the parse results and
+// the final source, e.g. to find out why a setting does not take effect.
+// The trace is written to the output of the flag set, values of sensitive
+// bindings are redacted.
+//
+// Debug is enabled by default if the ENFLAG_DEBUG environment variable
+// is set to a true value, e.g. ENFLAG_DEBUG=1.
+var Debug = debugEnv()
+
+func debugEnv() bool {
+	v, _ := strconv.ParseBool(os.Getenv("ENFLAG_DEBUG"))
+	return v
+}
+
+// trace writes a line of the resolution trace of the binding.
+func (b *binding) trace(format string, args ...any) {
+	if !Debug {
+		return
+	}
+
+	name := b.envName
+	if name == "" {
+		name = "-" + b.flagName
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprintf(flagSet().Output(), "enflag: %s: %s\n", name, msg)
+}
+
+// traceValue quotes a raw value for the trace.
+func (b *binding) traceValue(s string) string {
+	if b.sensitive {
+		return redacted
+	}
+
+	return strconv.Quote(s)
+}
+
+// traceResolved writes the final values and sources of the bindings.
+func traceResolved(bindings []*binding) {
+	if !Debug {
+		return
+	}
+
+	for _, b := range bindings {
+		b.trace("resolved to %v from %s", b.dumpValue(), b.source)
+	}
+}
@@ -0,0 +1,51 @@
+package enflag
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+)
+
+func TestDebug(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	Debug = true
+	defer func() { Debug = false }()
+
+	var buf bytes.Buffer
+	flag.CommandLine.SetOutput(&buf)
+
+	os.Setenv("DEBUG_PORT", "http")
+	os.Setenv("DEBUG_TOKEN", "secret")
+	os.Setenv("DEBUG_HOST", "db.local")
+	for _, name := range []string{"DEBUG_PORT", "DEBUG_TOKEN", "DEBUG_HOST"} {
+		defer os.Unsetenv(name)
+	}
+	os.Args = []string{"cmd", "-debug-host", "localhost"}
+
+	var port, workers int
+	var token, host string
+	Var(&port).WithDefault(8080).WithEnvAliases("DEBUG_PORT_OLD").BindEnv("DEBUG_PORT")
+	Var(&token).Sensitive().BindEnv("DEBUG_TOKEN")
+	Var(&host).Bind("DEBUG_HOST", "debug-host")
+	Var(&workers).WithDefault(4).WithDefaultFromEnv("DEBUG_FALLBACK").BindEnv("DEBUG_MISSING")
+	Parse()
+
+	want := `enflag: DEBUG_HOST: flag -debug-host = "localhost"
+enflag: DEBUG_HOST: parsed "localhost"
+enflag: DEBUG_PORT: env DEBUG_PORT = "http"
+enflag: DEBUG_PORT: error: strconv.Atoi: parsing "http": invalid syntax
+enflag: DEBUG_TOKEN: env DEBUG_TOKEN = ***
+enflag: DEBUG_TOKEN: parsed ***
+enflag: DEBUG_HOST: set by flag, the environment is not read
+enflag: DEBUG_MISSING: env DEBUG_MISSING is not set
+enflag: DEBUG_MISSING: fallback env DEBUG_FALLBACK is not set
+enflag: DEBUG_PORT: resolved to 8080 from default
+enflag: DEBUG_TOKEN: resolved to *** from env
+enflag: DEBUG_HOST: resolved to localhost from flag
+enflag: DEBUG_MISSING: resolved to 4 from default
+`
+	checkVal(t, want, buf.String())
+}
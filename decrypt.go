@@ -0,0 +1,42 @@
+package enflag
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// EncryptedPrefix marks encrypted values. The rest of such a value
+// is the base64-encoded ciphertext, e.g. "enc:c2VjcmV0".
+const EncryptedPrefix = "enc:"
+
+// Decrypt is a function used to decrypt values prefixed with EncryptedPrefix,
+// e.g. with AES-GCM, age or KMS. It can be overridden per binding with
+// WithDecrypt. Values are kept as is if it is nil, which is the default.
+var Decrypt func(ciphertext []byte) ([]byte, error)
+
+// decrypt returns the plaintext of an encrypted value,
+// or the value itself if it is not encrypted.
+func (b *binding) decrypt(s string) (string, error) {
+	fn := b.decryptFunc
+	if fn == nil {
+		fn = Decrypt
+	}
+
+	if fn == nil || !strings.HasPrefix(s, EncryptedPrefix) {
+		return s, nil
+	}
+
+	enc := strings.TrimSpace(s[len(EncryptedPrefix):])
+	ciphertext, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+
+	plaintext, err := fn(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
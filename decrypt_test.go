@@ -0,0 +1,62 @@
+package enflag
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestDecrypt(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	xor := func(key byte) func([]byte) ([]byte, error) {
+		return func(b []byte) ([]byte, error) {
+			res := make([]byte, len(b))
+			for i := range b {
+				res[i] = b[i] ^ key
+			}
+			return res, nil
+		}
+	}
+	encrypt := func(s string, key byte) string {
+		b, _ := xor(key)([]byte(s))
+		return EncryptedPrefix + base64.StdEncoding.EncodeToString(b)
+	}
+
+	Decrypt = xor(1)
+	defer func() { Decrypt = nil }()
+
+	os.Setenv("DECRYPT_PASSWORD", encrypt("qwerty", 1))
+	os.Setenv("DECRYPT_PORT", encrypt("5432", 1))
+	os.Setenv("DECRYPT_TOKEN", encrypt("token", 2))
+	os.Setenv("DECRYPT_PLAIN", "plain")
+	os.Setenv("DECRYPT_INVALID", "enc:!!!")
+	os.Setenv("DECRYPT_FAILED", encrypt("x", 1))
+
+	var password, token, plain, invalid, failed string
+	var port int
+	Var(&password).BindEnv("DECRYPT_PASSWORD")
+	Var(&port).BindEnv("DECRYPT_PORT")
+	Var(&token).WithDecrypt(xor(2)).BindEnv("DECRYPT_TOKEN")
+	Var(&plain).BindEnv("DECRYPT_PLAIN")
+	Var(&invalid).WithDefault("a").BindEnv("DECRYPT_INVALID")
+	VarFunc(&failed, func(s string) (string, error) { return s, nil }).
+		WithDefault("b").
+		WithDecrypt(func([]byte) ([]byte, error) { return nil, errors.New("wrong key") }).
+		BindEnv("DECRYPT_FAILED")
+	Parse()
+
+	checkVal(t, "qwerty", password)
+	checkVal(t, 5432, port)
+	checkVal(t, "token", token)
+	checkVal(t, "plain", plain)
+	checkVal(t, "a", invalid)
+	checkVal(t, "b", failed)
+
+	// values are kept as is without a decryption function
+	Decrypt = nil
+	s, _ := (&binding{}).decrypt("enc:abc")
+	checkVal(t, "enc:abc", s)
+}
@@ -0,0 +1,68 @@
+package enflag
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dirValues holds the values loaded by LoadDir, keyed by
+// environment variable names.
+var dirValues map[string]string
+
+// dirFiles holds the paths of the files loaded by LoadDir,
+// keyed by environment variable names.
+var dirFiles map[string]string
+
+// LoadDir reads key-value pairs from the files of the given directory, one
+// file per key, as projected by Kubernetes Secrets and ConfigMaps. File names
+// are used as environment variable names and file contents as their values.
+//
+// Loaded values are used for bindings whose environment variables are not set,
+// and their source is SourceDir. Hidden files and subdirectories are skipped. Values from later calls
+// take precedence.
+//
+// LoadDir must be called before Parse.
+func LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string, len(entries))
+	files := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		// Kubernetes projects keys as symlinks, so the target is checked
+		path := filepath.Join(dir, e.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		values[e.Name()] = string(data)
+		files[e.Name()] = path
+	}
+
+	if dirValues == nil {
+		dirValues = make(map[string]string, len(values))
+		dirFiles = make(map[string]string, len(files))
+	}
+
+	for k, v := range values {
+		dirValues[k] = v
+		dirFiles[k] = files[k]
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package enflag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDir(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	// emulate the layout of a mounted Kubernetes Secret
+	dir := t.TempDir()
+	data := filepath.Join(dir, "..data")
+	if err := os.Mkdir(data, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{"DIR_PASSWORD": "qwerty", "DIR_USER": "guest"} {
+		if err := os.WriteFile(filepath.Join(data, k), []byte(v), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.Join("..data", k), filepath.Join(dir, k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := LoadDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("DIR_USER", "admin")
+
+	var password, user string
+	Var(&password).BindEnv("DIR_PASSWORD")
+	Var(&user).BindEnv("DIR_USER")
+	Parse()
+
+	checkVal(t, "qwerty", password)
+	checkVal(t, "admin", user)
+	checkVal(t, SourceDir, Source("DIR_PASSWORD"))
+	checkVal(t, SourceEnv, Source("DIR_USER"))
+	checkVal(t, "dir", SourceDir.String())
+	checkVal(t, 2, len(dirValues))
+
+	if err := LoadDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,405 @@
+package enflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// docOrder returns the bindings in the order of the documentation:
+// bindings without a group first, then the groups ordered by the lowest
+// weight of their bindings, and the bindings of each group by weight.
+// Ties keep the registration order.
+func docOrder() []*binding {
+	type groupRank struct {
+		weight int
+		first  int
+	}
+	all := registered()
+	ranks := make(map[string]groupRank)
+	for i, b := range all {
+		r, ok := ranks[b.group]
+		if !ok {
+			r = groupRank{weight: b.weight, first: i}
+		}
+		if b.weight < r.weight {
+			r.weight = b.weight
+		}
+		ranks[b.group] = r
+	}
+
+	res := append([]*binding(nil), all...)
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.group != b.group {
+			if a.group == "" || b.group == "" {
+				return a.group == ""
+			}
+
+			ra, rb := ranks[a.group], ranks[b.group]
+			if ra.weight != rb.weight {
+				return ra.weight < rb.weight
+			}
+			return ra.first < rb.first
+		}
+
+		return a.weight < b.weight
+	})
+
+	return res
+}
+
+// GenerateMarkdown writes a Markdown table describing all registered
+// bindings to w: environment variables, flags, types, default values
+// and usage messages. Defaults of sensitive bindings are redacted.
+// The bindings are ordered by group and weight, see WithWeight.
+func GenerateMarkdown(w io.Writer) error {
+	var buf bytes.Buffer
+
+	buf.WriteString("| Environment variable | Flag | Type | Default | Description |\n")
+	buf.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, b := range docOrder() {
+		fmt.Fprintf(
+			&buf,
+			"| %s | %s | %s | %s | %s |\n",
+			mdCode(b.envName),
+			mdCode(flagPrefix(b.flagName)),
+			mdCode(b.typeName),
+			mdCode(b.defaultString()),
+			mdEscape(b.envDescription()),
+		)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// GenerateEnvExample writes a commented .env example file to w with every
+// registered environment variable, its type, default value and usage message.
+// Values of sensitive bindings are left empty. The variables are ordered
+// like in GenerateMarkdown.
+func GenerateEnvExample(w io.Writer) error {
+	var buf bytes.Buffer
+
+	for _, b := range docOrder() {
+		if b.envName == "" {
+			continue
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+
+		if usage := b.envDescription(); usage != "" {
+			for _, line := range strings.Split(usage, "\n") {
+				fmt.Fprintf(&buf, "# %s\n", line)
+			}
+		}
+
+		typ := b.typeName
+		if b.sensitive {
+			typ += ", sensitive"
+		}
+		fmt.Fprintf(&buf, "# Type: %s\n", typ)
+
+		var val string
+		if !b.sensitive {
+			val = envQuote(b.envDefault())
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", b.envName, val)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// envDefault returns the default value in the format it is expected
+// in the environment variable: slice elements are joined by the slice
+// separator, and map entries are formatted as key-value pairs.
+func (b *binding) envDefault() string {
+	switch def := b.def.(type) {
+	case url.Values:
+		return def.Encode()
+	case map[string][]string:
+		return url.Values(def).Encode()
+	case http.Header:
+		keys := make([]string, 0, len(def))
+		for k := range def {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var res []string
+		for _, k := range keys {
+			for _, v := range def[k] {
+				res = append(res, k+"="+v)
+			}
+		}
+		return strings.Join(res, "&")
+	}
+
+	raw, err := json.Marshal(dumpValue(b.def))
+	if err != nil {
+		return ""
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return ""
+	}
+
+	switch v := v.(type) {
+	case []any:
+		res := make([]string, len(v))
+		for i := range v {
+			res[i] = formatValue(v[i])
+		}
+		return strings.Join(res, b.sliceSep)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		res := make([]string, len(keys))
+		for i, k := range keys {
+			res[i] = k + b.kvSep + formatValue(v[k])
+		}
+		return strings.Join(res, b.sliceSep)
+	}
+
+	return formatValue(v)
+}
+
+func envQuote(s string) string {
+	if strings.ContainsAny(s, " \t\n#'\"\\$") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// GenerateJSONSchema writes a JSON Schema document describing all registered
+// bindings to w. The schema matches the output of Dump with the DumpJSON
+// format: properties are keyed by environment variable names, or by flag names
+// if a binding has no environment variable.
+//
+// Allowed values, ranges and default values are included, NonEmpty
+// bindings are listed as required. Sensitive bindings, which Dump redacts,
+// are described without their type, default value and constraints.
+func GenerateJSONSchema(w io.Writer) error {
+	schema := struct {
+		Schema     string       `json:"$schema"`
+		Type       string       `json:"type"`
+		Properties schemaFields `json:"properties"`
+		Required   []string     `json:"required,omitempty"`
+	}{
+		Schema: "https://json-schema.org/draft/2020-12/schema",
+		Type:   "object",
+	}
+
+	for _, b := range docOrder() {
+		// Dump writes "***" for sensitive bindings of any type,
+		// so only their description is kept
+		var prop schemaProperty
+		if !b.sensitive {
+			prop = bindingSchema(b)
+		}
+		prop.Description = b.envUsage
+		if prop.Description == "" {
+			prop.Description = b.flagUsage
+		}
+
+		schema.Properties = append(schema.Properties, schemaField{name: b.key(), prop: prop})
+		if b.nonEmpty {
+			schema.Required = append(schema.Required, b.key())
+		}
+	}
+
+	res, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(res, '\n'))
+	return err
+}
+
+// bindingSchema describes the type, the default value and the constraints
+// of the binding.
+func bindingSchema(b *binding) schemaProperty {
+	prop := schemaType(b.typeName)
+	if formatValue(b.def) != "" {
+		prop.Default = dumpValue(b.def)
+	}
+	if prop.Items != nil {
+		// each element of a slice must be allowed, see WithAllowed
+		prop.Items.Enum = b.allowed
+	} else {
+		prop.Enum = b.allowed
+	}
+	if b.example != "" {
+		prop.Examples = []string{b.example}
+	}
+	if b.rng != nil && (prop.Type == "integer" || prop.Type == "number") {
+		if b.rng.min != nil {
+			prop.Minimum = b.rng.min
+		}
+		if b.rng.max != nil {
+			prop.Maximum = b.rng.max
+		}
+	}
+
+	return prop
+}
+
+type schemaProperty struct {
+	Type                 string          `json:"type,omitempty"`
+	Description          string          `json:"description,omitempty"`
+	Items                *schemaProperty `json:"items,omitempty"`
+	AdditionalProperties *schemaProperty `json:"additionalProperties,omitempty"`
+	Default              any             `json:"default,omitempty"`
+	Enum                 []string        `json:"enum,omitempty"`
+	Examples             []string        `json:"examples,omitempty"`
+	Minimum              any             `json:"minimum,omitempty"`
+	Maximum              any             `json:"maximum,omitempty"`
+}
+
+type schemaField struct {
+	name string
+	prop schemaProperty
+}
+
+// schemaFields preserves the order of the properties in the schema.
+type schemaFields []schemaField
+
+func (f schemaFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, field := range f {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		key, _ := json.Marshal(field.name)
+		val, err := json.Marshal(field.prop)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(val)
+	}
+	buf.WriteString("}")
+
+	return buf.Bytes(), nil
+}
+
+// schemaType maps a Go type name to the JSON Schema type of its Dump output.
+// Unknown types are left unconstrained.
+func schemaType(typeName string) schemaProperty {
+	typeName = strings.TrimPrefix(typeName, "*")
+
+	switch typeName {
+	case "int", "int8", "int16", "int32", "int64", "big.Int":
+		return schemaProperty{Type: "integer"}
+	case "enflag.Counter":
+		return schemaProperty{Type: "integer"}
+	case "enflag.ByteSize":
+		return schemaProperty{Type: "integer", Minimum: 0}
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return schemaProperty{Type: "integer", Minimum: 0}
+	case "float32", "float64", "json.Number":
+		return schemaProperty{Type: "number"}
+	case "bool":
+		return schemaProperty{Type: "boolean"}
+	case "string", "[]uint8", "big.Float", "time.Time", "time.Duration", "time.Location",
+		"enflag.DSN", "enflag.HostPort", "enflag.UUID", "enflag.SemVer", "enflag.CronExpr", "slog.Level", "fs.FileMode", "os.File", "url.URL", "net.IP", "net.IPNet",
+		"netip.Addr", "netip.Prefix", "netip.AddrPort", "mail.Address", "regexp.Regexp":
+		return schemaProperty{Type: "string"}
+	}
+
+	if typeName == "http.Header" || typeName == "url.Values" {
+		typeName = "map[string][]string"
+	}
+
+	if elem, ok := cutPrefix(typeName, "[]"); ok {
+		items := schemaType(elem)
+		return schemaProperty{Type: "array", Items: &items}
+	}
+
+	if elem, ok := cutPrefix(typeName, "map[string]"); ok {
+		values := schemaType(elem)
+		return schemaProperty{Type: "object", AdditionalProperties: &values}
+	}
+
+	return schemaProperty{}
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+
+	return s[len(prefix):], true
+}
+
+// defaultString returns the default value formatted for documentation.
+func (b *binding) defaultString() string {
+	s := formatValue(b.def)
+	if s != "" && b.sensitive {
+		return redacted
+	}
+
+	return s
+}
+
+// formatValue formats v for documentation: strings are used as is,
+// other values are JSON-encoded. Nil and empty values result in "".
+func formatValue(v any) string {
+	switch v := dumpValue(v).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		res, err := json.Marshal(v)
+		if err != nil || string(res) == "null" {
+			return ""
+		}
+		return string(res)
+	}
+}
+
+func flagPrefix(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	return "-" + name
+}
+
+func mdCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + strings.ReplaceAll(s, "|", "\\|") + "`"
+}
+
+func mdEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,207 @@
+package enflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+)
+
+// DumpFormat is an output format of Dump.
+type DumpFormat int
+
+const (
+	// DumpJSON formats the configuration as a JSON object.
+	DumpJSON DumpFormat = iota
+
+	// DumpYAML formats the configuration as a YAML mapping.
+	DumpYAML
+)
+
+// Dump writes the resolved values of all registered bindings to w
+// in the given format, in the order the bindings were created.
+// Each value is keyed by its environment variable name, or by its flag name
+// if the binding has no environment variable. Values of sensitive bindings
+// are replaced with "***".
+//
+// Dump should be called after Parse.
+func Dump(w io.Writer, format DumpFormat) error {
+	var buf bytes.Buffer
+
+	switch format {
+	case DumpJSON:
+		buf.WriteString("{")
+	case DumpYAML:
+	default:
+		return fmt.Errorf("unknown dump format %d", format)
+	}
+
+	bindings := registered()
+	for i, b := range bindings {
+		key := b.key()
+		val, err := json.Marshal(b.dumpValue())
+		if err != nil {
+			return fmt.Errorf("unable to format %q: %w", key, err)
+		}
+
+		if format == DumpYAML {
+			if !yamlPlainKey.MatchString(key) {
+				key = fmt.Sprintf("%q", key)
+			}
+			fmt.Fprintf(&buf, "%s: %s\n", key, val)
+			continue
+		}
+
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, "\n  %q: %s", key, val)
+	}
+
+	if format == DumpJSON {
+		if len(bindings) > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+var yamlPlainKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// key returns the name identifying the binding in the generated output.
+func (b *binding) key() string {
+	if b.envName != "" {
+		return b.envName
+	}
+
+	return b.flagName
+}
+
+// redacted replaces values of sensitive bindings in generated output.
+const redacted = "***"
+
+func (b *binding) dumpValue() any {
+	if b.sensitive {
+		return redacted
+	}
+
+	return dumpValue(b.value())
+}
+
+// dumpValue converts values whose JSON representation is not human-friendly.
+func dumpValue(v any) any {
+	switch v := v.(type) {
+	case time.Duration:
+		return v.String()
+	case []time.Duration:
+		res := make([]string, len(v))
+		for i := range v {
+			res[i] = v[i].String()
+		}
+		return res
+	case map[string]time.Duration:
+		res := make(map[string]string, len(v))
+		for k := range v {
+			res[k] = v[k].String()
+		}
+		return res
+	case os.FileMode:
+		return fmt.Sprintf("%#o", uint32(v.Perm()))
+	case *time.Location:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	case url.URL:
+		return v.String()
+	case *url.URL:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	case UUID:
+		return v.String()
+	case SemVer:
+		return v.String()
+	case []UUID:
+		res := make([]string, len(v))
+		for i := range v {
+			res[i] = v[i].String()
+		}
+		return res
+	case DSN:
+		return v.Redacted()
+	case *DSN:
+		if v == nil {
+			return nil
+		}
+		return v.Redacted()
+	case []DSN:
+		res := make([]string, len(v))
+		for i := range v {
+			res[i] = v[i].Redacted()
+		}
+		return res
+	case []url.URL:
+		res := make([]string, len(v))
+		for i := range v {
+			res[i] = v[i].String()
+		}
+		return res
+	case []*url.URL:
+		res := make([]any, len(v))
+		for i := range v {
+			if v[i] != nil {
+				res[i] = v[i].String()
+			}
+		}
+		return res
+	case mail.Address:
+		return v.String()
+	case *mail.Address:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	case []mail.Address:
+		res := make([]string, len(v))
+		for i := range v {
+			res[i] = v[i].String()
+		}
+		return res
+	case *os.File:
+		if v == nil {
+			return nil
+		}
+		return v.Name()
+	case *regexp.Regexp:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	case net.IPNet:
+		return v.String()
+	case *net.IPNet:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	case []net.IPNet:
+		res := make([]string, len(v))
+		for i := range v {
+			res[i] = v[i].String()
+		}
+		return res
+	}
+
+	return v
+}
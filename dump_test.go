@@ -0,0 +1,95 @@
+package enflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDump(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("DUMP_PORT", "8080")
+
+	var port int
+	var host string
+	var ttl time.Duration
+	var base *url.URL
+	var tags []string
+	var secret []byte
+	var subnet net.IPNet
+	var mode os.FileMode
+	var dsn DSN
+
+	Var(&port).Bind("DUMP_PORT", "dump-port")
+	Var(&host).WithDefault("localhost").BindFlag("dump-host")
+	Var(&ttl).WithDefault(time.Minute).BindEnv("DUMP_TTL")
+	Var(&base).WithDefault(&url.URL{Scheme: "https", Host: "example.com"}).BindEnv("DUMP_URL")
+	Var(&tags).WithDefault([]string{"a", "b"}).BindEnv("DUMP_TAGS")
+	Var(&secret).WithDefault([]byte("qwerty")).Sensitive().BindEnv("DUMP_SECRET")
+	Var(&subnet).WithDefault(net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)}).BindEnv("DUMP_SUBNET")
+	Var(&mode).WithDefault(0644).BindEnv("DUMP_MODE")
+	VarNamed(&dsn).WithDefault("postgres://app:secret@db/app").BindEnv("DUMP_DSN")
+	Parse()
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Dump(&buf, DumpJSON); err != nil {
+			t.Fatal(err)
+		}
+
+		want := `{
+  "DUMP_PORT": 8080,
+  "dump-host": "localhost",
+  "DUMP_TTL": "1m0s",
+  "DUMP_URL": "https://example.com",
+  "DUMP_TAGS": ["a","b"],
+  "DUMP_SECRET": "***",
+  "DUMP_SUBNET": "10.0.0.0/8",
+  "DUMP_MODE": "0644",
+  "DUMP_DSN": "postgres://app:xxxxx@db/app"
+}
+`
+		checkVal(t, want, buf.String())
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Dump(&buf, DumpYAML); err != nil {
+			t.Fatal(err)
+		}
+
+		want := `DUMP_PORT: 8080
+dump-host: "localhost"
+DUMP_TTL: "1m0s"
+DUMP_URL: "https://example.com"
+DUMP_TAGS: ["a","b"]
+DUMP_SECRET: "***"
+DUMP_SUBNET: "10.0.0.0/8"
+DUMP_MODE: "0644"
+DUMP_DSN: "postgres://app:xxxxx@db/app"
+`
+		checkVal(t, want, buf.String())
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		if err := Dump(&bytes.Buffer{}, DumpFormat(10)); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestDumpNilElements(t *testing.T) {
+	urls := []*url.URL{{Scheme: "https", Host: "example.com"}, nil}
+
+	res, err := json.Marshal(dumpValue(urls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkVal(t, `["https://example.com",null]`, string(res))
+}
@@ -0,0 +1,188 @@
+package enflag
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// Lookuper looks up environment variables.
+// The second result reports whether the variable is present.
+type Lookuper interface {
+	Lookup(key string) (string, bool)
+}
+
+// LookuperFunc adapts a function like os.LookupEnv to the Lookuper interface.
+type LookuperFunc func(key string) (string, bool)
+
+// Lookup calls f(key).
+func (f LookuperFunc) Lookup(key string) (string, bool) {
+	return f(key)
+}
+
+// MapLookuper is a Lookuper backed by a map, e.g. a fake environment in tests.
+type MapLookuper map[string]string
+
+// Lookup returns the value of the key.
+func (m MapLookuper) Lookup(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// Keys returns all keys of the map. It is used by StrictEnvPrefix.
+func (m MapLookuper) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Env is the source of environment variables. If it is nil,
+// which is the default, the environment of the process is used.
+//
+//	enflag.Env = enflag.MapLookuper{"PORT": "8080"}
+var Env Lookuper
+
+// expandEnv replaces references to environment variables in s, see ExpandEnv.
+func expandEnv(s string) string {
+	return os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+
+		return getenv(name)
+	})
+}
+
+// expandHome replaces a leading ~ or $HOME in the path with the home
+// directory. Paths like ~user/dir are kept as is.
+func expandHome(path string) (string, error) {
+	var rest string
+	switch {
+	case path == "~" || strings.HasPrefix(path, "~/"):
+		rest = path[1:]
+	case path == "$HOME" || strings.HasPrefix(path, "$HOME/"):
+		rest = path[len("$HOME"):]
+	default:
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return home + rest, nil
+}
+
+func getenv(key string) string {
+	v, _ := lookupEnv(key)
+	return v
+}
+
+func lookupEnv(key string) (string, bool) {
+	if Env != nil {
+		return Env.Lookup(key)
+	}
+
+	return os.LookupEnv(key)
+}
+
+// EmptyValue controls how environment variables which are set,
+// but empty, are handled.
+type EmptyValue int
+
+const (
+	// EmptyIgnore treats empty variables as unset, so the default is used.
+	EmptyIgnore EmptyValue = iota
+
+	// EmptyOverride parses empty variables like other values, e.g. an empty
+	// string overrides the default, while an empty number is an error.
+	EmptyOverride
+
+	// EmptyError passes ErrEmptyEnv to ErrorHandlerFunc for empty variables.
+	EmptyError
+)
+
+// EmptyEnv controls how empty environment variables are handled by default.
+// It can be overridden per binding with WithEmptyEnv.
+var EmptyEnv = EmptyIgnore
+
+// StrictEnvPrefix enables the strict mode: Parse reports every environment
+// variable with this prefix that is not used by any binding, e.g. a typo like
+// MYAPP_PROT instead of MYAPP_PORT. Errors are handled by ErrorHandlerFunc.
+// The strict mode is disabled if the prefix is empty, which is the default.
+//
+// If Env is set, only a Lookuper with a "Keys() []string" method,
+// like MapLookuper, can be checked.
+//
+// The check runs once, on the first call to Parse, so the variables of
+// bindings created after it, e.g. lazily by a sub-module, are reported
+// as unknown. Such bindings must be created before the first Parse.
+var StrictEnvPrefix = ""
+
+// checkUnknownEnv reports the environment variables with StrictEnvPrefix
+// that are not used by any binding.
+func checkUnknownEnv() {
+	if StrictEnvPrefix == "" {
+		return
+	}
+
+	known := make(map[string]bool)
+	for _, b := range registered() {
+		if b.envName != "" {
+			for _, name := range append([]string{b.envName}, b.envAliases...) {
+				known[name] = true
+				if EnvFileSuffix != "" {
+					known[name+EnvFileSuffix] = true
+				}
+			}
+		}
+
+		// the fallback variable and the KV key, which may be named
+		// like a variable, are used as well
+		if b.defaultEnv != "" {
+			known[b.defaultEnv] = true
+		}
+		if b.kvKey != "" {
+			known[b.kvKey] = true
+		}
+	}
+
+	var unknown []string
+	for _, key := range envKeys() {
+		if strings.HasPrefix(key, StrictEnvPrefix) && !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, key := range unknown {
+		reportError(ErrUnknownEnv, "", nil, key, "")
+	}
+}
+
+// envKeys returns the names of all environment variables and the keys
+// loaded by LoadEnvFile and LoadDir.
+func envKeys() []string {
+	var keys []string
+	if Env == nil {
+		for _, kv := range os.Environ() {
+			k, _, _ := strings.Cut(kv, "=")
+			keys = append(keys, k)
+		}
+	} else if lister, ok := Env.(interface{ Keys() []string }); ok {
+		keys = lister.Keys()
+	}
+
+	for k := range envFileValues {
+		keys = append(keys, k)
+	}
+	for k := range dirValues {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
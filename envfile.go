@@ -0,0 +1,88 @@
+package enflag
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envFileValues holds the values loaded by LoadEnvFile, keyed by
+// environment variable names.
+var envFileValues map[string]string
+
+// envFiles holds the paths of the .env files loaded by LoadEnvFile,
+// keyed by environment variable names.
+var envFiles map[string]string
+
+// LoadEnvFile reads environment variables from a .env file with one
+// KEY=VALUE pair per line. Empty lines and lines starting with # are skipped,
+// an "export " prefix is allowed, and values may be quoted: double-quoted
+// values are unquoted like Go strings, single-quoted values are kept as is.
+//
+// Loaded values are used for bindings whose environment variables are not set,
+// before the values loaded by LoadDir, and their source is SourceEnvFile.
+// The process environment is not changed. Values from later calls
+// take precedence.
+//
+// LoadEnvFile must be called before Parse.
+func LoadEnvFile(path string) error {
+	values, err := readEnvFile(path)
+	if err != nil {
+		return err
+	}
+
+	if envFileValues == nil {
+		envFileValues = make(map[string]string, len(values))
+		envFiles = make(map[string]string, len(values))
+	}
+
+	for k, v := range values {
+		envFileValues[k] = v
+		envFiles[k] = path
+	}
+
+	return nil
+}
+
+// readEnvFile parses the .env file. Invalid lines are reported by their
+// numbers only, since the values may be secrets.
+func readEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, val, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%s:%d: invalid line, KEY=VALUE expected", path, i+1)
+		}
+
+		val = strings.TrimSpace(val)
+		switch {
+		case len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"':
+			if val, err = strconv.Unquote(val); err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid quoted value", path, i+1)
+			}
+		case len(val) >= 2 && val[0] == '\'' && val[len(val)-1] == '\'':
+			val = val[1 : len(val)-1]
+		default:
+			// an unquoted value ends at a comment
+			if i := strings.Index(val, " #"); i >= 0 {
+				val = strings.TrimSpace(val[:i])
+			}
+		}
+
+		values[key] = val
+	}
+
+	return values, nil
+}
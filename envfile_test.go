@@ -0,0 +1,76 @@
+package enflag
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadEnvFile(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	data := `# database
+ENVFILE_HOST=db.local
+export ENVFILE_PORT = 5432
+ENVFILE_PASSWORD="qw\"erty"
+ENVFILE_DSN='postgres://u:p@db/app?x=#1'
+ENVFILE_NAME=app # the name
+ENVFILE_USER=guest
+ENVFILE_EMPTY=
+`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadEnvFile(path); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("ENVFILE_USER", "admin")
+	defer os.Unsetenv("ENVFILE_USER")
+
+	var host, password, dsn, name, user, empty string
+	var port int
+	Var(&host).BindEnv("ENVFILE_HOST")
+	Var(&port).BindEnv("ENVFILE_PORT")
+	Var(&password).BindEnv("ENVFILE_PASSWORD")
+	Var(&dsn).BindEnv("ENVFILE_DSN")
+	Var(&name).BindEnv("ENVFILE_NAME")
+	Var(&user).BindEnv("ENVFILE_USER")
+	Var(&empty).WithDefault("none").BindEnv("ENVFILE_EMPTY")
+	Parse()
+
+	checkVal(t, "db.local", host)
+	checkVal(t, 5432, port)
+	checkVal(t, `qw"erty`, password)
+	checkVal(t, "postgres://u:p@db/app?x=#1", dsn)
+	checkVal(t, "app", name)
+	checkVal(t, "admin", user)
+	checkVal(t, "none", empty)
+	checkVal(t, SourceEnvFile, Source("ENVFILE_HOST"))
+	checkVal(t, SourceEnv, Source("ENVFILE_USER"))
+	checkVal(t, "envfile", SourceEnvFile.String())
+
+	if err := LoadEnvFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestLoadEnvFileInvalid(t *testing.T) {
+	reset()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("ENVFILE_OK=1\nsecret-without-key\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := LoadEnvFile(path)
+	if err == nil || !strings.HasSuffix(err.Error(), ":2: invalid line, KEY=VALUE expected") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "secret") {
+		t.Errorf("the line is echoed: %v", err)
+	}
+	checkVal(t, 0, len(envFileValues))
+}
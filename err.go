@@ -1,9 +1,10 @@
 package enflag
 
 import (
-	"flag"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 )
 
 // ErrorHandlerFunc is a function called after a value parser returns an error.
@@ -24,20 +25,260 @@ var OnErrorIgnore = func(err error, rawVal string, target any, envName string, f
 // OnErrorLogAndContinue prints the error message but continues execution.
 // If a default value is specified, it will be used.
 var OnErrorLogAndContinue = func(err error, rawVal string, target any, envName string, flagName string) {
-	_, _ = err, rawVal
+	_ = rawVal
 
 	var msg string
-	if envName != "" {
-		msg = fmt.Sprintf("unable to parse env-variable %q as type %T\n", envName, target)
+	if errs, ok := err.(Errors); ok {
+		for _, e := range errs {
+			var pErr *ParseError
+			if errors.As(e, &pErr) {
+				msg += errorMessage(e, pErr.Type, pErr.Env, pErr.Flag)
+			}
+		}
+	} else {
+		msg = errorMessage(err, fmt.Sprintf("%T", target), envName, flagName)
+	}
+
+	flagSet().Output().Write([]byte(msg))
+}
+
+// errorMessage returns the line printed by OnErrorLogAndContinue.
+func errorMessage(err error, typeName string, envName string, flagName string) string {
+	var msg string
+	if errors.Is(err, ErrUnknownEnv) {
+		msg = fmt.Sprintf("unknown env-variable %q", envName)
+	} else if errors.Is(err, ErrEmptyEnv) {
+		msg = fmt.Sprintf("env-variable %q is empty", envName)
+	} else if envName != "" {
+		msg = fmt.Sprintf("unable to parse env-variable %q as type %s", envName, typeName)
 	} else if flagName != "" {
-		msg = fmt.Sprintf("unable to parse flag %q as type %T\n", flagName, target)
+		msg = fmt.Sprintf("unable to parse flag %q as type %s", flagName, typeName)
+	} else {
+		msg = "invalid configuration"
+	}
+
+	// Parser errors may contain the raw value, so only validation
+	// messages are printed.
+	var vErr *validationError
+	var mErr *MissingError
+	if errors.As(err, &vErr) {
+		msg += ": " + vErr.Error()
+	} else if errors.As(err, &mErr) {
+		msg += ": " + mErr.Error()
+	}
+
+	return msg + "\n"
+}
+
+// handleError passes the error wrapped in a ParseError to the error handler
+// of the binding, or to ErrorHandlerFunc. The binding may be nil.
+//
+// The raw values of sensitive bindings are not passed, and parser and
+// validation errors, which may contain them, are replaced with ErrRedacted.
+func handleError[T any](b *binding, err error, target *T, rawVal, envName string, flagName string) {
+	if b != nil && b.sensitive {
+		rawVal = ""
+		err = redact(err)
+	}
+	if b != nil {
+		b.trace("error: %v", err)
+	}
+
+	err = &ParseError{
+		Env:  envName,
+		Flag: flagName,
+		Raw:  rawVal,
+		Type: fmt.Sprintf("%T", *target),
+		Err:  err,
+	}
+
+	if b != nil && b.errorHandler != nil {
+		b.errorHandler(err, rawVal, *target, envName, flagName)
+		return
+	}
+
+	reportError(err, rawVal, *target, envName, flagName)
+}
+
+// CollectErrors enables the collect-all mode: instead of being passed
+// to ErrorHandlerFunc one by one, all errors are passed together as Errors
+// by Parse, so every invalid value is reported at once.
+var CollectErrors = false
+
+// parseErrs holds the errors passed to ErrorHandlerFunc, see TryParse.
+var parseErrs []error
+
+// collectedErrs holds the errors not yet passed to ErrorHandlerFunc
+// in the collect-all mode.
+var collectedErrs []error
+
+// tryParsing is set while TryParse is running.
+var tryParsing bool
+
+// reportError records the error and passes it to ErrorHandlerFunc,
+// unless TryParse is running or the errors are collected.
+func reportError(err error, rawVal string, target any, envName string, flagName string) {
+	var pErr *ParseError
+	if !errors.As(err, &pErr) {
+		pErr = &ParseError{Env: envName, Flag: flagName, Raw: rawVal, Err: err}
+		if target != nil {
+			pErr.Type = fmt.Sprintf("%T", target)
+		}
+		err = pErr
+	}
+
+	parseErrs = append(parseErrs, err)
+	if tryParsing {
+		return
+	}
+
+	if CollectErrors {
+		collectedErrs = append(collectedErrs, err)
+		return
+	}
+
+	ErrorHandlerFunc(err, rawVal, target, envName, flagName)
+}
+
+// flushErrors passes the collected errors to ErrorHandlerFunc.
+func flushErrors() {
+	if len(collectedErrs) == 0 {
+		return
+	}
+
+	errs := Errors(collectedErrs)
+	collectedErrs = nil
+	ErrorHandlerFunc(errs, "", nil, "", "")
+}
+
+// Errors is a list of errors, each of them is a *ParseError.
+// It is passed to ErrorHandlerFunc in the collect-all mode, see CollectErrors.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Is reports whether any of the errors matches the target, see errors.Is.
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
 	}
 
-	flag.CommandLine.Output().Write([]byte(msg))
+	return false
 }
 
-func handleError[T any](err error, target *T, rawVal, envName string, flagName string) {
-	ErrorHandlerFunc(err, rawVal, *target, envName, flagName)
+// As finds the first of the errors that matches the target, see errors.As.
+func (e Errors) As(target any) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
 }
 
 var osExitFunc = os.Exit
+
+// ErrUnknownEnv is passed to ErrorHandlerFunc for unused environment
+// variables in the strict mode, see StrictEnvPrefix.
+var ErrUnknownEnv = errors.New("unknown environment variable")
+
+// ErrEmptyEnv is passed to ErrorHandlerFunc for environment variables
+// which are set, but empty, if EmptyError is used, see EmptyEnv.
+var ErrEmptyEnv = errors.New("empty environment variable")
+
+// ErrRedacted replaces parser and validation errors of sensitive bindings,
+// as they may contain the raw value, see Binding.Sensitive.
+var ErrRedacted = errors.New("invalid value (redacted)")
+
+// redact replaces the error with ErrRedacted unless it is known
+// not to contain the raw value. The errors of custom validation functions,
+// like ExistingFile, may contain it, so only the built-in constraints
+// are kept.
+func redact(err error) error {
+	var cErr constraintError
+	var mErr *MissingError
+	if errors.As(err, &cErr) || errors.As(err, &mErr) || errors.Is(err, ErrEmptyEnv) {
+		return err
+	}
+
+	return ErrRedacted
+}
+
+// ParseError describes a value which failed to parse or validate.
+// It is passed to the error handlers and returned by TryParse.
+type ParseError struct {
+	// names of the source, one of them is empty
+	Env  string
+	Flag string
+
+	Raw string
+
+	// Type is the Go type of the bound variable, e.g. "time.Duration".
+	Type string
+
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	var src string
+	switch {
+	case e.Env != "":
+		src = fmt.Sprintf("env-variable %q", e.Env)
+	case e.Flag != "":
+		src = fmt.Sprintf("flag %q", e.Flag)
+	default:
+		return fmt.Sprintf("invalid configuration: %v", e.Err)
+	}
+
+	if e.Type == "" {
+		return fmt.Sprintf("%s: %v", src, e.Err)
+	}
+
+	return fmt.Sprintf("unable to parse %s as type %s: %v", src, e.Type, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// MissingError describes a required value which is not provided
+// by any source, see NonEmpty.
+type MissingError struct {
+	Env  string
+	Flag string
+}
+
+func (e *MissingError) Error() string {
+	return "must not be empty"
+}
+
+// validationError wraps an error returned by a validation function.
+type validationError struct {
+	err error
+}
+
+func (e *validationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *validationError) Unwrap() error {
+	return e.err
+}
+
+// constraintError is returned by the built-in constraints like WithRange,
+// WithAllowed and WithPattern. Its message never contains the value.
+type constraintError string
+
+func (e constraintError) Error() string {
+	return string(e)
+}
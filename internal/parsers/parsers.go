@@ -1,10 +1,19 @@
 package parsers
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/big"
 	"net"
+	"net/http"
+	"net/mail"
 	"net/url"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -21,10 +30,44 @@ func String(s string) (string, error) {
 	return s, nil
 }
 
+func Bytes(s string) ([]byte, error) {
+	return []byte(s), nil
+}
+
 func Inte64(s string) (int64, error) {
 	return strconv.ParseInt(s, 10, 64)
 }
 
+// Int parses a signed integer of the given bit size, failing on overflow.
+func Int[T ~int8 | ~int16 | ~int32](bitSize int) func(string) (T, error) {
+	return func(s string) (T, error) {
+		v, err := strconv.ParseInt(s, 10, bitSize)
+		if err != nil {
+			return 0, err
+		}
+		return T(v), nil
+	}
+}
+
+// UintN parses an unsigned integer of the given bit size, failing on overflow.
+func UintN[T ~uint8 | ~uint16 | ~uint32](bitSize int) func(string) (T, error) {
+	return func(s string) (T, error) {
+		v, err := strconv.ParseUint(s, 10, bitSize)
+		if err != nil {
+			return 0, err
+		}
+		return T(v), nil
+	}
+}
+
+func Float32(s string) (float32, error) {
+	v, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		return 0, err
+	}
+	return float32(v), nil
+}
+
 func Uint(s string) (uint, error) {
 	v, err := strconv.ParseUint(s, 10, 64)
 	if err != nil {
@@ -62,3 +105,258 @@ func IP(s string) (net.IP, error) {
 	}
 	return ip, nil
 }
+
+func IPNet(s string) (net.IPNet, error) {
+	n, err := IPNetPtr(s)
+	if err != nil {
+		return net.IPNet{}, err
+	}
+	return *n, nil
+}
+
+func IPNetPtr(s string) (*net.IPNet, error) {
+	_, n, err := net.ParseCIDR(s)
+	return n, err
+}
+
+func MailAddress(s string) (mail.Address, error) {
+	a, err := mail.ParseAddress(s)
+	if err != nil {
+		return mail.Address{}, err
+	}
+	return *a, nil
+}
+
+func MailAddressList(s string) ([]mail.Address, error) {
+	list, err := mail.ParseAddressList(s)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]mail.Address, len(list))
+	for i, a := range list {
+		res[i] = *a
+	}
+	return res, nil
+}
+
+// ExtendedBool parses a boolean like strconv.ParseBool, additionally accepting
+// "yes", "no", "on", "off", "enabled" and "disabled" in any case.
+func ExtendedBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "on", "enabled":
+		return true, nil
+	case "no", "off", "disabled":
+		return false, nil
+	}
+
+	return strconv.ParseBool(s)
+}
+
+// ExtendedDuration parses a duration like time.ParseDuration,
+// additionally accepting "d" (24h) and "w" (7d) units, e.g. "30d" or "1w2d12h".
+func ExtendedDuration(s string) (time.Duration, error) {
+	orig := s
+
+	var sign time.Duration = 1
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+
+	if s == "" {
+		return 0, errors.New("invalid duration " + strconv.Quote(orig))
+	}
+
+	var total time.Duration
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] == '.' || ('0' <= s[i] && s[i] <= '9')) {
+			i++
+		}
+		j := i
+		for j < len(s) && s[j] != '.' && (s[j] < '0' || s[j] > '9') {
+			j++
+		}
+
+		num, unit := s[:i], s[i:j]
+		s = s[j:]
+		if num == "" {
+			return 0, errors.New("invalid duration " + strconv.Quote(orig))
+		}
+
+		var multiplier time.Duration = 1
+		switch unit {
+		case "d":
+			unit, multiplier = "h", 24
+		case "w":
+			unit, multiplier = "h", 24*7
+		}
+
+		d, err := time.ParseDuration(num + unit)
+		if err != nil {
+			return 0, errors.New("invalid duration " + strconv.Quote(orig))
+		}
+
+		if d > math.MaxInt64/multiplier || total > math.MaxInt64-d*multiplier {
+			return 0, errors.New("invalid duration " + strconv.Quote(orig))
+		}
+		total += d * multiplier
+	}
+
+	return sign * total, nil
+}
+
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+}
+
+// ByteSize parses a human-readable size like "512KiB", "10MB" or "1.5G"
+// into a number of bytes. Decimal units (K, MB, G, ...) are powers of 1000,
+// binary units (Ki, MiB, Gi, ...) are powers of 1024.
+func ByteSize(s string) (int64, error) {
+	i := 0
+	for i < len(s) && (s[i] == '.' || ('0' <= s[i] && s[i] <= '9')) {
+		i++
+	}
+
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	multiplier, ok := byteUnits[unit]
+	if num == "" || !ok {
+		return 0, errors.New("invalid byte size " + strconv.Quote(s))
+	}
+
+	if !strings.Contains(num, ".") {
+		v, err := strconv.ParseInt(num, 10, 64)
+		if err != nil || v > math.MaxInt64/int64(multiplier) {
+			return 0, errors.New("invalid byte size " + strconv.Quote(s))
+		}
+		return v * int64(multiplier), nil
+	}
+
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v*multiplier >= math.MaxInt64 {
+		return 0, errors.New("invalid byte size " + strconv.Quote(s))
+	}
+	return int64(v * multiplier), nil
+}
+
+func BigInt(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, errors.New("invalid integer " + strconv.Quote(s))
+	}
+	return v, nil
+}
+
+// BigFloat parses a float with a precision derived from the length of s,
+// but at least 64 bits, so long decimal values are not rounded to float64.
+func BigFloat(s string) (*big.Float, error) {
+	// 4 bits are more than log2(10) per decimal digit
+	prec := uint(len(s)) * 4
+	if prec < 64 {
+		prec = 64
+	}
+
+	v, ok := new(big.Float).SetPrec(prec).SetString(s)
+	if !ok {
+		return nil, errors.New("invalid float " + strconv.Quote(s))
+	}
+	return v, nil
+}
+
+// JSONNumber validates that s is a JSON number literal.
+func JSONNumber(s string) (json.Number, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+
+	var v any
+	err := dec.Decode(&v)
+	n, ok := v.(json.Number)
+	if err != nil || !ok || dec.More() || strings.TrimSpace(s) != string(n) {
+		return "", errors.New("invalid number " + strconv.Quote(s))
+	}
+	return n, nil
+}
+
+// Fixed decodes s into dst, failing if the decoded length differs from len(dst).
+func Fixed(dst []byte, s string, decode func(string) ([]byte, error)) error {
+	v, err := decode(s)
+	if err != nil {
+		return err
+	}
+
+	if len(v) != len(dst) {
+		return fmt.Errorf("invalid length: expected %d bytes, got %d", len(dst), len(v))
+	}
+
+	copy(dst, v)
+	return nil
+}
+
+// FileMode parses octal permission bits like "0644" or "0o755".
+func FileMode(s string) (fs.FileMode, error) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "0o"), 8, 32)
+	if err != nil || v > uint64(fs.ModePerm) {
+		return 0, errors.New("invalid file mode " + strconv.Quote(s))
+	}
+	return fs.FileMode(v), nil
+}
+
+// File opens the named file for reading, "-" means the standard input.
+func File(s string) (*os.File, error) {
+	if s == "-" {
+		return os.Stdin, nil
+	}
+	return os.Open(s)
+}
+
+// Header parses header entries in the "key=value" or "key: value" form.
+// Entries are separated by newlines, or by "&" if there are no newlines.
+// Repeated keys add values.
+func Header(s string) (http.Header, error) {
+	sep := "&"
+	if strings.Contains(s, "\n") {
+		sep = "\n"
+	}
+
+	h := make(http.Header)
+	for _, entry := range strings.Split(s, sep) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		i := strings.IndexAny(entry, ":=")
+		if i <= 0 {
+			return nil, errors.New("invalid header entry")
+		}
+
+		h.Add(strings.TrimSpace(entry[:i]), strings.TrimSpace(entry[i+1:]))
+	}
+
+	return h, nil
+}
@@ -0,0 +1,75 @@
+package enflag
+
+// KVSource is a remote key-value store, e.g. Consul or etcd.
+// The second result reports whether the key exists.
+type KVSource interface {
+	Get(key string) (string, bool, error)
+}
+
+// KVSourceFunc adapts a function to the KVSource interface.
+type KVSourceFunc func(key string) (string, bool, error)
+
+// Get calls f(key).
+func (f KVSourceFunc) Get(key string) (string, bool, error) {
+	return f(key)
+}
+
+// KV is the store consulted for bindings whose environment variables
+// are not set. Keys are KVPrefix followed by the environment variable name,
+// or by the key set with WithKVKey. The store is not used if KV is nil,
+// which is the default.
+//
+// For example, with the Consul client:
+//
+//	kv := consul.KV()
+//	enflag.KV = enflag.KVSourceFunc(func(key string) (string, bool, error) {
+//		pair, _, err := kv.Get(key, nil)
+//		if err != nil || pair == nil {
+//			return "", false, err
+//		}
+//		return string(pair.Value), true, nil
+//	})
+//	enflag.KVPrefix = "my-service/"
+//
+// KV must be set before Parse. Flags take precedence over values
+// from the store.
+var KV KVSource
+
+// KVPrefix is prepended to the keys of the KV store, e.g. "my-service/".
+var KVPrefix string
+
+func (b *binding) kvPath() string {
+	key := b.kvKey
+	if key == "" {
+		key = b.envName
+	}
+	if key == "" {
+		return ""
+	}
+
+	return KVPrefix + key
+}
+
+// readKV returns the prepared value from the KV store and its key.
+// Errors of the store are handled in place.
+func readKV[T any](b *binding, ptr *T) (string, string, bool) {
+	key := b.kvPath()
+	if KV == nil || key == "" {
+		return "", "", false
+	}
+
+	kvVal, ok, err := KV.Get(key)
+	if err != nil {
+		handleError(b, err, ptr, "", key, "")
+		return "", "", false
+	}
+	if !ok || kvVal == "" {
+		b.trace("kv %s is not set", key)
+		return "", "", false
+	}
+	b.trace("kv %s = %s", key, b.traceValue(kvVal))
+
+	b.fromKV = true
+	s, ok := prepare(b, ptr, kvVal, key, "")
+	return s, key, ok
+}
@@ -0,0 +1,60 @@
+package enflag
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestKV(t *testing.T) {
+	var errs []string
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		errs = append(errs, envName+": "+errors.Unwrap(err).Error())
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	store := map[string]string{
+		"svc/KV_HOST":    "consul",
+		"svc/KV_PORT":    "8500",
+		"svc/db/user":    "admin",
+		"svc/KV_TIMEOUT": "1m",
+	}
+	KV = KVSourceFunc(func(key string) (string, bool, error) {
+		if key == "svc/KV_BROKEN" {
+			return "", false, errors.New("connection refused")
+		}
+		v, ok := store[key]
+		return v, ok, nil
+	})
+	KVPrefix = "svc/"
+	defer func() { KV, KVPrefix = nil, "" }()
+
+	os.Setenv("KV_PORT", "9000")
+	defer os.Unsetenv("KV_PORT")
+	os.Args = []string{"cmd", "-kv-timeout", "5s"}
+
+	var host, user, broken string
+	var port int
+	var timeout string
+	hostB := Var(&host)
+	hostB.BindEnv("KV_HOST")
+	Var(&port).BindEnv("KV_PORT")
+	Var(&user).WithKVKey("db/user").BindFlag("kv-user")
+	Var(&broken).WithDefault("fallback").BindEnv("KV_BROKEN")
+	Var(&timeout).Bind("KV_TIMEOUT", "kv-timeout")
+	Parse()
+
+	checkVal(t, "consul", host)
+	checkVal(t, SourceKV, hostB.Source())
+	checkVal(t, 9000, port)
+	checkVal(t, SourceEnv, Source("KV_PORT"))
+	checkVal(t, "admin", user)
+	checkVal(t, "fallback", broken)
+	checkVal(t, "5s", timeout)
+	checkVal(t, SourceFlag, Source("KV_TIMEOUT"))
+	checkSlice(t, []string{"svc/KV_BROKEN: connection refused"}, errs)
+
+	info, _ := Lookup("kv-user")
+	checkVal(t, "svc/db/user", info.KVKey)
+}
@@ -0,0 +1,43 @@
+package enflag
+
+import "strings"
+
+// nameMapper derives flag names from environment variable names for BindAuto.
+var nameMapper = envToFlag
+
+// SetNameMapper replaces the convention used by BindAuto to derive flag names
+// from environment variable names, e.g. to use dots or camelCase in flags.
+// By default, SCREAMING_SNAKE_CASE is converted to kebab-case.
+// A nil mapper restores the default.
+//
+//	enflag.SetNameMapper(func(envName string) string {
+//	    return strings.ReplaceAll(strings.ToLower(envName), "_", ".")
+//	})
+func SetNameMapper(mapper func(envName string) (flagName string)) {
+	if mapper == nil {
+		mapper = envToFlag
+	}
+
+	nameMapper = mapper
+}
+
+// autoNames returns the environment variable and the flag names for BindAuto.
+// Names without lowercase letters are environment variable names,
+// others are flag names converted with the default convention.
+func autoNames(name string) (string, string) {
+	if strings.ToUpper(name) == name {
+		return name, nameMapper(name)
+	}
+
+	return flagToEnv(name), name
+}
+
+// envToFlag converts SCREAMING_SNAKE_CASE to kebab-case.
+func envToFlag(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+// flagToEnv converts kebab-case to SCREAMING_SNAKE_CASE.
+func flagToEnv(name string) string {
+	return strings.ReplaceAll(strings.ToUpper(name), "-", "_")
+}
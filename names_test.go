@@ -0,0 +1,57 @@
+package enflag
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBindAuto(t *testing.T) {
+	reset()
+
+	os.Setenv("AUTO_DB_HOST", "db")
+	os.Setenv("AUTO_DB_PORT", "5432")
+
+	var host string
+	var port int
+	var user string
+	Var(&host).BindAuto("AUTO_DB_HOST")
+	VarFunc(&port, strconv.Atoi).BindAuto("auto-db-port")
+	Var(&user).BindAuto("auto-db-user")
+	Parse()
+
+	checkVal(t, "db", host)
+	checkVal(t, 5432, port)
+
+	for env, flag := range map[string]string{
+		"AUTO_DB_HOST": "auto-db-host",
+		"AUTO_DB_PORT": "auto-db-port",
+		"AUTO_DB_USER": "auto-db-user",
+	} {
+		info, ok := Lookup(env)
+		checkVal(t, true, ok)
+		checkVal(t, flag, info.FlagName)
+	}
+}
+
+func TestSetNameMapper(t *testing.T) {
+	reset()
+
+	SetNameMapper(func(envName string) string {
+		return strings.ReplaceAll(strings.ToLower(envName), "_", ".")
+	})
+	defer SetNameMapper(nil)
+
+	var host string
+	Var(&host).BindAuto("MAPPER_DB_HOST")
+
+	info, _ := Lookup("MAPPER_DB_HOST")
+	checkVal(t, "mapper.db.host", info.FlagName)
+
+	SetNameMapper(nil)
+	Var(&host).BindAuto("MAPPER_HTTP_HOST")
+
+	info, _ = Lookup("MAPPER_HTTP_HOST")
+	checkVal(t, "mapper-http-host", info.FlagName)
+}
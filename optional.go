@@ -0,0 +1,41 @@
+package enflag
+
+// Optional is a bound value which records whether any source provided it,
+// so an explicit zero value can be told apart from an unset one:
+//
+//	var retries enflag.Optional[int]
+//	enflag.VarOptional(&retries).Bind("RETRIES", "retries")
+//	enflag.Parse()
+//
+//	if n, ok := retries.Get(); ok {
+//	    client.SetRetries(n)
+//	}
+type Optional[T any] struct {
+	Value T
+
+	// Valid reports whether Value was provided by a source
+	// rather than being the default value.
+	Valid bool
+}
+
+// Get returns the value and whether it was provided by a source.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Valid
+}
+
+// VarOptional creates a new Binding for the value of p.
+// Valid is set by Parse.
+func VarOptional[T builtin](p *Optional[T]) *Binding[T] {
+	b := Var(&p.Value)
+	b.setter = func() { p.Valid = b.source != SourceDefault }
+
+	return b
+}
+
+// VarOptionalFunc is like VarOptional, but with a custom parser, see VarFunc.
+func VarOptionalFunc[T any](p *Optional[T], parser func(string) (T, error)) *CustomBinding[T] {
+	b := VarFunc(&p.Value, parser)
+	b.setter = func() { p.Valid = b.source != SourceDefault }
+
+	return b
+}
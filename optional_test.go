@@ -0,0 +1,32 @@
+package enflag
+
+import (
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestOptional(t *testing.T) {
+	reset()
+
+	os.Setenv("OPTIONAL_RETRIES", "0")
+	defer os.Unsetenv("OPTIONAL_RETRIES")
+	os.Args = []string{"cmd", "-optional-level", "2"}
+
+	var retries, workers Optional[int]
+	var level Optional[int64]
+	VarOptional(&retries).WithDefault(3).Bind("OPTIONAL_RETRIES", "optional-retries")
+	VarOptional(&workers).WithDefault(4).BindEnv("OPTIONAL_WORKERS")
+	VarOptionalFunc(&level, func(s string) (int64, error) {
+		return strconv.ParseInt(s, 10, 64)
+	}).BindFlag("optional-level")
+	Parse()
+
+	n, ok := retries.Get()
+	checkVal(t, 0, n)
+	checkVal(t, true, ok)
+	checkVal(t, 4, workers.Value)
+	checkVal(t, false, workers.Valid)
+	checkVal(t, int64(2), level.Value)
+	checkVal(t, true, level.Valid)
+}
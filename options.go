@@ -0,0 +1,151 @@
+package enflag
+
+import "regexp"
+
+// BindingTemplate holds options shared by several bindings, so they are
+// defined once. It is created by Template and applied by the From method
+// of the bindings:
+//
+//	secret := enflag.Template().Sensitive().FromFile().WithTrimSpace()
+//	enflag.Var(&conf.DBPassword).From(secret).BindEnv("DB_PASSWORD")
+//	enflag.Var(&conf.APIKey).From(secret).BindEnv("API_KEY")
+//
+// Options set after From override the ones of the template.
+type BindingTemplate struct {
+	opts []func(b *binding)
+}
+
+// Template creates a new empty BindingTemplate.
+func Template() *BindingTemplate {
+	return &BindingTemplate{}
+}
+
+func (t *BindingTemplate) add(f func(b *binding)) *BindingTemplate {
+	t.opts = append(t.opts, f)
+	return t
+}
+
+func (t *BindingTemplate) apply(b *binding) {
+	for _, f := range t.opts {
+		f(b)
+	}
+}
+
+// From applies the options of the template to the Binding.
+func (b *Binding[T]) From(t *BindingTemplate) *Binding[T] {
+	t.apply(&b.binding)
+	return b
+}
+
+// From applies the options of the template to the CustomBinding.
+func (b *CustomBinding[T]) From(t *BindingTemplate) *CustomBinding[T] {
+	t.apply(&b.binding)
+	return b
+}
+
+// WithValidate adds a validation function, which receives the parsed value
+// of the binding's type.
+func (t *BindingTemplate) WithValidate(f func(v any) error) *BindingTemplate {
+	return t.add(func(b *binding) {
+		b.validators = append(b.validators, f)
+	})
+}
+
+// WithPattern adds a validation of string values against re,
+// see Binding.WithPattern.
+func (t *BindingTemplate) WithPattern(re *regexp.Regexp) *BindingTemplate {
+	return t.WithValidate(func(v any) error {
+		return matchPattern(re, v)
+	})
+}
+
+// WithSliceSeparator sets the separator for parsing slices.
+func (t *BindingTemplate) WithSliceSeparator(sep string) *BindingTemplate {
+	return t.add(func(b *binding) { b.sliceSep = sep })
+}
+
+// WithCSV enables CSV parsing of slices, see Binding.WithCSV.
+func (t *BindingTemplate) WithCSV() *BindingTemplate {
+	return t.add(func(b *binding) { b.csv = true })
+}
+
+// WithKeyValueSeparator sets the separator between keys and values of maps.
+func (t *BindingTemplate) WithKeyValueSeparator(sep string) *BindingTemplate {
+	return t.add(func(b *binding) { b.kvSep = sep })
+}
+
+// WithTimeLayout sets the layout for parsing time.
+func (t *BindingTemplate) WithTimeLayout(layout string) *BindingTemplate {
+	return t.add(func(b *binding) { b.timeLayout = layout })
+}
+
+// WithDecodeStringFunc sets the string-to-[]byte decoder.
+func (t *BindingTemplate) WithDecodeStringFunc(f func(string) ([]byte, error)) *BindingTemplate {
+	return t.add(func(b *binding) { b.setDecoder(f) })
+}
+
+// WithExtendedDuration enables day and week units for durations,
+// see Binding.WithExtendedDuration.
+func (t *BindingTemplate) WithExtendedDuration() *BindingTemplate {
+	return t.add(func(b *binding) { b.extDuration = true })
+}
+
+// WithExtendedBool enables extended boolean values,
+// see Binding.WithExtendedBool.
+func (t *BindingTemplate) WithExtendedBool() *BindingTemplate {
+	return t.add(func(b *binding) { b.extBool = true })
+}
+
+// FromFile treats the values as paths to files to read, see Binding.FromFile.
+func (t *BindingTemplate) FromFile() *BindingTemplate {
+	return t.add(func(b *binding) { b.setFromFile() })
+}
+
+// WithTrimSpace trims white space from the values.
+func (t *BindingTemplate) WithTrimSpace() *BindingTemplate {
+	return t.add(func(b *binding) { b.trimSpace = true })
+}
+
+// WithEmptyEnv sets the policy for empty environment variables.
+func (t *BindingTemplate) WithEmptyEnv(v EmptyValue) *BindingTemplate {
+	return t.add(func(b *binding) {
+		b.emptyEnv = v
+		b.emptyEnvSet = true
+	})
+}
+
+// ExpandHome expands a leading ~ in the values, see Binding.ExpandHome.
+func (t *BindingTemplate) ExpandHome() *BindingTemplate {
+	return t.add(func(b *binding) { b.expandHome = true })
+}
+
+// Resolve enables the registered resolvers for the values,
+// see Binding.Resolve.
+func (t *BindingTemplate) Resolve() *BindingTemplate {
+	return t.add(func(b *binding) { b.resolveRefs = true })
+}
+
+// NoExpand disables ExpandEnv for the bindings.
+func (t *BindingTemplate) NoExpand() *BindingTemplate {
+	return t.add(func(b *binding) { b.noExpand = true })
+}
+
+// WithDecrypt sets the decryption function, see Binding.WithDecrypt.
+func (t *BindingTemplate) WithDecrypt(fn func(ciphertext []byte) ([]byte, error)) *BindingTemplate {
+	return t.add(func(b *binding) { b.decryptFunc = fn })
+}
+
+// WithGroup sets the group of the flags in the usage message.
+func (t *BindingTemplate) WithGroup(name string) *BindingTemplate {
+	return t.add(func(b *binding) { b.group = name })
+}
+
+// WithErrorHandler sets the error handler, see Binding.WithErrorHandler.
+func (t *BindingTemplate) WithErrorHandler(f func(err error, rawVal string, target any, envName string, flagName string)) *BindingTemplate {
+	return t.add(func(b *binding) { b.errorHandler = f })
+}
+
+// Sensitive marks the values as sensitive, see Binding.Sensitive.
+func (t *BindingTemplate) Sensitive() *BindingTemplate {
+	return t.add(func(b *binding) { b.sensitive = true })
+}
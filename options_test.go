@@ -0,0 +1,52 @@
+package enflag
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestTemplate(t *testing.T) {
+	var errs []string
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		errs = append(errs, envName+": "+errors.Unwrap(err).Error())
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	os.Setenv("TPL_HOSTS", " a;b ")
+	os.Setenv("TPL_ZONES", "x;y;Z")
+	os.Setenv("TPL_START", "2024-05-01")
+	os.Setenv("TPL_KEYS", "k1;k2")
+	for _, name := range []string{"TPL_HOSTS", "TPL_ZONES", "TPL_START", "TPL_KEYS"} {
+		defer os.Unsetenv(name)
+	}
+
+	list := Template().
+		WithSliceSeparator(";").
+		WithTrimSpace().
+		WithPattern(regexp.MustCompile("^[a-z0-9]+$")).
+		WithGroup("lists")
+
+	var hosts, zones, keys []string
+	var start time.Time
+	Var(&hosts).From(list).BindEnv("TPL_HOSTS")
+	Var(&zones).From(list).BindEnv("TPL_ZONES")
+	Var(&start).From(Template().WithTimeLayout(time.RFC3339)).WithTimeLayout("2006-01-02").BindEnv("TPL_START")
+	VarSliceFunc(&keys, func(s string) (string, error) { return s, nil }).
+		From(list).From(Template().Sensitive()).
+		BindEnv("TPL_KEYS")
+	Parse()
+
+	checkSlice(t, []string{"a", "b"}, hosts)
+	checkSlice(t, nil, zones)
+	checkVal(t, "2024-05-01", start.Format("2006-01-02"))
+	checkSlice(t, []string{"k1", "k2"}, keys)
+	checkSlice(t, []string{`TPL_ZONES: must match pattern "^[a-z0-9]+$"`}, errs)
+
+	info, _ := Lookup("TPL_KEYS")
+	checkVal(t, true, info.Sensitive)
+	checkVal(t, "lists", info.Group)
+}
@@ -0,0 +1,276 @@
+package enflag
+
+import (
+	"flag"
+	"reflect"
+	"sync"
+)
+
+// ValueSource describes where the value of a binding came from.
+type ValueSource int
+
+const (
+	// SourceDefault means that no data source provided a value,
+	// and the default value is used.
+	SourceDefault ValueSource = iota
+
+	// SourceEnv means that the value was parsed from an environment variable.
+	SourceEnv
+
+	// SourceFlag means that the value was parsed from a command-line flag.
+	SourceFlag
+
+	// SourceKV means that the value was read from the KV store.
+	SourceKV
+
+	// SourceDir means that the value was read from a file loaded by LoadDir.
+	SourceDir
+
+	// SourceEnvFile means that the value was read from a .env file
+	// loaded by LoadEnvFile.
+	SourceEnvFile
+)
+
+func (s ValueSource) String() string {
+	switch s {
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	case SourceKV:
+		return "kv"
+	case SourceDir:
+		return "dir"
+	case SourceEnvFile:
+		return "envfile"
+	default:
+		return "default"
+	}
+}
+
+// registry holds all bindings in the order they were bound.
+var registry []*binding
+
+// mu guards the registry, the parse hooks and the flag set
+// while the bindings are created.
+var mu sync.Mutex
+
+// registered returns the bindings registered so far.
+func registered() []*binding {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return registry[:len(registry):len(registry)]
+}
+
+func register[T any](b *binding, p *T, def T) {
+	b.typeName = reflect.TypeOf(p).Elem().String()
+	b.def = def
+	b.target = ref[T]{p: p}
+
+	registry = append(registry, b)
+
+	if b.group != "" && b.flagName != "" {
+		installGroupedUsage()
+	}
+}
+
+// target gives untyped access to the bound variable of a binding.
+type target interface {
+	get() any
+	set(v any)
+}
+
+// ref holds a single pointer, so storing it in a target does not allocate.
+type ref[T any] struct {
+	p *T
+}
+
+func (r ref[T]) get() any {
+	return *r.p
+}
+
+func (r ref[T]) set(v any) {
+	*r.p = v.(T)
+}
+
+func (b *binding) value() any {
+	return b.target.get()
+}
+
+func (b *binding) restore(v any) {
+	b.target.set(v)
+}
+
+// Reset removes all bindings, parse hooks and values loaded by LoadDir,
+// so a new set of bindings can be created, e.g. in tests or in tools
+// parsing several configurations.
+//
+// FlagSet is set to nil. If enflag registered flags in flag.CommandLine
+// or parsed it, flag.CommandLine is replaced with a new empty flag set with
+// the same name and error handling, since flags cannot be redefined, and
+// flag.Usage is restored if enflag replaced it, see WithGroup. A custom
+// FlagSet is not changed. Global options like ErrorHandlerFunc are not changed.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	registry = nil
+	parseHooks = nil
+	parseErrs = nil
+	collectedErrs = nil
+	dirValues = nil
+	dirFiles = nil
+	envFileValues = nil
+	envFiles = nil
+
+	parsed = false
+	parsedBindings = 0
+	parsedHooks = 0
+
+	if groupUsageSet == flag.CommandLine {
+		flag.Usage = prevFlagUsage
+	}
+	if usedFlagSet == flag.CommandLine {
+		flag.CommandLine = flag.NewFlagSet(flag.CommandLine.Name(), flag.CommandLine.ErrorHandling())
+	}
+
+	FlagSet = nil
+	usedFlagSet = nil
+	groupUsageSet = nil
+}
+
+// lookup returns the first binding with the given environment variable
+// or flag name, or nil if there is none.
+func lookup(name string) *binding {
+	if name == "" {
+		return nil
+	}
+
+	for _, b := range registered() {
+		if b.envName == name || b.flagName == name {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// Source reports where the value of the binding with the given
+// environment variable or flag name came from.
+// SourceDefault is returned for unknown names.
+//
+// The result is final only after Parse has been called.
+func Source(name string) ValueSource {
+	if b := lookup(name); b != nil {
+		return b.source
+	}
+
+	return SourceDefault
+}
+
+// IsSet reports whether the value of the binding with the given
+// environment variable or flag name was provided by any data source,
+// as opposed to the default value being used.
+//
+// The result is final only after Parse has been called.
+func IsSet(name string) bool {
+	return Source(name) != SourceDefault
+}
+
+// BindingInfo describes a registered binding.
+type BindingInfo struct {
+	EnvName   string
+	FlagName  string
+	FlagUsage string
+	EnvUsage  string
+
+	// Type is the Go type of the bound variable, e.g. "time.Duration".
+	Type string
+
+	Default   any
+	Value     any
+	Source    ValueSource
+	Sensitive bool
+
+	// options
+	EnvAliases []string
+	DefaultEnv string
+	Example    string
+	KVKey      string
+	Group      string
+	Weight     int
+	Deprecated string
+	Allowed    []string
+	Min        any
+	Max        any
+	NonEmpty   bool
+}
+
+// Lookup returns information about the binding with the given
+// environment variable or flag name. The second result reports
+// whether such a binding exists.
+//
+// Value and Source are final only after Parse has been called.
+func Lookup(name string) (BindingInfo, bool) {
+	b := lookup(name)
+	if b == nil {
+		return BindingInfo{}, false
+	}
+
+	return b.info(), true
+}
+
+// VisitAll calls fn for every registered binding in the order
+// they were bound, e.g. to log the configuration at startup.
+//
+// Values and sources are final only after Parse has been called.
+func VisitAll(fn func(b BindingInfo)) {
+	for _, b := range registered() {
+		fn(b.info())
+	}
+}
+
+// Bindings returns information about all registered bindings
+// in the order they were bound.
+//
+// Values and sources are final only after Parse has been called.
+func Bindings() []BindingInfo {
+	bindings := registered()
+	res := make([]BindingInfo, len(bindings))
+	for i, b := range bindings {
+		res[i] = b.info()
+	}
+
+	return res
+}
+
+func (b *binding) info() BindingInfo {
+	info := BindingInfo{
+		EnvName:   b.envName,
+		FlagName:  b.flagName,
+		FlagUsage: b.usage(),
+		EnvUsage:  b.envDescription(),
+		Type:      b.typeName,
+		Default:   b.def,
+		Value:     b.value(),
+		Source:    b.source,
+		Sensitive: b.sensitive,
+
+		EnvAliases: append([]string(nil), b.envAliases...),
+		DefaultEnv: b.defaultEnv,
+		Example:    b.example,
+		KVKey:      b.kvPath(),
+		Group:      b.group,
+		Weight:     b.weight,
+		Deprecated: b.deprecated,
+		Allowed:    append([]string(nil), b.allowed...),
+		NonEmpty:   b.nonEmpty,
+	}
+
+	if b.rng != nil {
+		info.Min, info.Max = b.rng.min, b.rng.max
+	}
+
+	return info
+}
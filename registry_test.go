@@ -0,0 +1,229 @@
+package enflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSource(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("SRC_HOST", "db.int")
+	os.Setenv("SRC_PORT", "5432")
+	os.Setenv("SRC_IDS", "1,2")
+	os.Setenv("SRC_BAD", "abc")
+
+	var host, user string
+	var port, bad int
+	var ids []int
+
+	hostB := Var(&host)
+	hostB.Bind("SRC_HOST", "src-host")
+	Var(&port).Bind("SRC_PORT", "src-port")
+	Var(&user).WithDefault("admin").Bind("SRC_USER", "src-user")
+	Var(&ids).BindEnv("SRC_IDS")
+	VarFunc(&bad, func(s string) (int, error) { return 0, os.ErrInvalid }).BindEnv("SRC_BAD")
+
+	flag.Set("src-port", "6432")
+	Parse()
+
+	checkVal(t, SourceEnv, hostB.Source())
+	checkVal(t, SourceEnv, Source("SRC_HOST"))
+	checkVal(t, SourceFlag, Source("src-port"))
+	checkVal(t, SourceFlag, Source("SRC_PORT"))
+	checkVal(t, SourceDefault, Source("SRC_USER"))
+	checkVal(t, SourceEnv, Source("SRC_IDS"))
+	checkVal(t, SourceDefault, Source("SRC_BAD"))
+	checkVal(t, SourceDefault, Source("UNKNOWN"))
+
+	checkVal(t, "flag", SourceFlag.String())
+}
+
+func TestLookup(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("LOOKUP_TTL", "5m")
+
+	var ttl time.Duration
+	var host string
+	var ids []uint
+
+	Var(&ttl).WithDefault(time.Minute).WithFlagUsage("cache ttl").Bind("LOOKUP_TTL", "lookup-ttl")
+	Var(&host).WithDefault("localhost").BindFlag("lookup-host")
+	Var(&ids).Sensitive().BindEnv("LOOKUP_IDS")
+	Parse()
+
+	checkVal(t, true, IsSet("LOOKUP_TTL"))
+	checkVal(t, true, IsSet("lookup-ttl"))
+	checkVal(t, false, IsSet("lookup-host"))
+	checkVal(t, false, IsSet("UNKNOWN"))
+
+	info, ok := Lookup("lookup-ttl")
+	checkVal(t, true, ok)
+	checkVal(t, "LOOKUP_TTL", info.EnvName)
+	checkVal(t, "lookup-ttl", info.FlagName)
+	checkVal(t, "cache ttl", info.FlagUsage)
+	checkVal(t, "time.Duration", info.Type)
+	checkVal(t, time.Minute, info.Default.(time.Duration))
+	checkVal(t, 5*time.Minute, info.Value.(time.Duration))
+	checkVal(t, SourceEnv, info.Source)
+	checkVal(t, false, info.Sensitive)
+
+	info, ok = Lookup("LOOKUP_IDS")
+	checkVal(t, true, ok)
+	checkVal(t, "[]uint", info.Type)
+	checkVal(t, true, info.Sensitive)
+
+	_, ok = Lookup("UNKNOWN")
+	checkVal(t, false, ok)
+}
+
+func TestVisitAll(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("VISIT_PORT", "8080")
+
+	var port int
+	var host string
+	var ttl time.Duration
+	Var(&port).Bind("VISIT_PORT", "visit-port")
+	Var(&host).WithDefault("localhost").BindFlag("visit-host")
+	Var(&ttl).WithFlagUsage("cache ttl").BindEnv("VISIT_TTL")
+	Parse()
+
+	var got []BindingInfo
+	VisitAll(func(b BindingInfo) {
+		got = append(got, b)
+	})
+
+	checkVal(t, 3, len(got))
+	checkVal(t, "VISIT_PORT", got[0].EnvName)
+	checkVal(t, 8080, got[0].Value.(int))
+	checkVal(t, SourceEnv, got[0].Source)
+	checkVal(t, "visit-host", got[1].FlagName)
+	checkVal(t, "localhost", got[1].Default.(string))
+	checkVal(t, "time.Duration", got[2].Type)
+	checkVal(t, "cache ttl", got[2].FlagUsage)
+}
+
+func TestBindings(t *testing.T) {
+	reset()
+
+	var port int
+	var level string
+	Var(&port).WithMin(1).WithMax(65535).WithGroup("HTTP").Bind("BINDINGS_PORT", "bindings-port")
+	Var(&level).
+		WithAllowed("debug", "info").
+		WithEnvAliases("BINDINGS_OLD_LEVEL").
+		Deprecated("use -log-level").
+		NonEmpty().
+		BindEnv("BINDINGS_LEVEL")
+
+	got := Bindings()
+	checkVal(t, 2, len(got))
+
+	checkVal(t, "HTTP", got[0].Group)
+	checkVal(t, 1, got[0].Min.(int))
+	checkVal(t, 65535, got[0].Max.(int))
+
+	checkSlice(t, []string{"debug", "info"}, got[1].Allowed)
+	checkSlice(t, []string{"BINDINGS_OLD_LEVEL"}, got[1].EnvAliases)
+	checkVal(t, "use -log-level", got[1].Deprecated)
+	checkVal(t, true, got[1].NonEmpty)
+	if got[1].Min != nil {
+		t.Errorf("unexpected min %v", got[1].Min)
+	}
+
+	// the result is a copy
+	got[1].Allowed[0] = "trace"
+	checkVal(t, "debug", Bindings()[1].Allowed[0])
+}
+
+func TestReset(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	var port int
+	Var(&port).WithGroup("Server").Bind("RESET_PORT", "reset-port")
+	OnParsed(func() error { return errors.New("invalid") })
+	name := flag.CommandLine.Name()
+
+	Reset()
+
+	checkVal(t, 0, len(Bindings()))
+	checkVal(t, 0, len(parseHooks))
+	checkVal(t, name, flag.CommandLine.Name())
+	checkVal(t, flag.ExitOnError, flag.CommandLine.ErrorHandling())
+
+	// the same flag can be bound again
+	os.Args = []string{"cmd", "-reset-port", "8080"}
+	Var(&port).Bind("RESET_PORT", "reset-port")
+	Parse()
+
+	checkVal(t, 8080, port)
+	checkVal(t, 1, len(Bindings()))
+
+	// flag.CommandLine and flag.Usage are kept if enflag did not use them
+	reset()
+	flag.CommandLine.String("reset-third-party", "", "")
+	defaultUsage := flag.Usage
+	defer func() { flag.Usage = defaultUsage }()
+	usage := func() {}
+	flag.Usage = usage
+
+	FlagSet = flag.NewFlagSet("custom", flag.ContinueOnError)
+	Var(&port).WithGroup("Server").Bind("RESET_PORT", "reset-port")
+	Reset()
+
+	if flag.CommandLine.Lookup("reset-third-party") == nil {
+		t.Error("flag.CommandLine was replaced")
+	}
+	if reflect.ValueOf(flag.Usage).Pointer() != reflect.ValueOf(usage).Pointer() {
+		t.Error("flag.Usage was replaced")
+	}
+}
+
+func TestConcurrentBind(t *testing.T) {
+	reset()
+
+	os.Setenv("CONC_3_7", "42")
+	defer os.Unsetenv("CONC_3_7")
+
+	const workers, perWorker = 8, 25
+	vals := make([][perWorker]int, workers)
+	var hooks int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				name := fmt.Sprintf("CONC_%d_%d", i, j)
+				Var(&vals[i][j]).Bind(name, strings.ToLower(strings.ReplaceAll(name, "_", "-")))
+				Lookup(name)
+			}
+			OnParsed(func() error {
+				atomic.AddInt32(&hooks, 1)
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	Parse()
+
+	checkVal(t, workers*perWorker, len(Bindings()))
+	checkVal(t, int32(workers), hooks)
+	checkVal(t, 42, vals[3][7])
+}
@@ -0,0 +1,97 @@
+package enflag
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Resolver returns the value referenced by ref, which is the part of a raw
+// value after the scheme, e.g. "/prod/db/password" for "ssm:/prod/db/password",
+// or "//bucket/key" for "s3://bucket/key".
+type Resolver func(ref string) (string, error)
+
+// resolvers holds the registered resolvers keyed by scheme.
+var resolvers = map[string]Resolver{}
+
+// RegisterResolver registers a resolver for values prefixed with the scheme
+// and a colon. Such values of the bindings marked with Resolve are replaced
+// with the result of the resolver before parsing, so secrets can stay out
+// of the process environment, e.g. for AWS SSM Parameter Store:
+//
+//	client := ssm.NewFromConfig(cfg)
+//	enflag.RegisterResolver("ssm", func(ref string) (string, error) {
+//		out, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+//			Name:           aws.String(ref),
+//			WithDecryption: aws.Bool(true),
+//		})
+//		if err != nil {
+//			return "", err
+//		}
+//		return aws.ToString(out.Parameter.Value), nil
+//	})
+//	enflag.Var(&conf.DBPassword).Resolve().BindEnv("DB_PASSWORD") // DB_PASSWORD=ssm:/prod/db/password
+//
+// Only the bindings marked with Resolve are resolved, for every scheme,
+// so values of other bindings which merely look like references, e.g. URLs
+// or SQLite DSNs like "file:test.db", are kept as is. No scheme is registered
+// by default: file URLs are dereferenced once ResolveFile is registered,
+// and other URL schemes like "s3" with resolvers of the application:
+//
+//	enflag.RegisterResolver("file", enflag.ResolveFile)
+//	enflag.Var(&conf.Token).Resolve().BindEnv("TOKEN") // TOKEN=file:///run/secrets/token
+//
+// Values of environment variables, flags, LoadDir and KV are resolved.
+// A nil resolver removes the scheme.
+// RegisterResolver must be called before Parse.
+func RegisterResolver(scheme string, r Resolver) {
+	if r == nil {
+		delete(resolvers, scheme)
+		return
+	}
+
+	resolvers[scheme] = r
+}
+
+// resolve returns the value referenced by s if it has a registered scheme,
+// or s itself otherwise.
+func resolve(s string) (string, error) {
+	scheme, ref, ok := strings.Cut(s, ":")
+	if !ok {
+		return s, nil
+	}
+
+	r := resolvers[scheme]
+	if r == nil {
+		return s, nil
+	}
+
+	return r(ref)
+}
+
+// ResolveFile is a Resolver for the "file" scheme, which is not registered
+// by default, see RegisterResolver.
+// It returns the contents of the file of a "file://" URL, e.g.
+// "file:///etc/app/token". Values without the slashes, like "file:test.db",
+// are kept as is.
+func ResolveFile(ref string) (string, error) {
+	if !strings.HasPrefix(ref, "//") {
+		return "file:" + ref, nil
+	}
+
+	u, err := url.Parse("file:" + ref)
+	if err != nil {
+		return "", err
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", fmt.Errorf("unsupported file URL host %q", u.Host)
+	}
+
+	data, err := os.ReadFile(u.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
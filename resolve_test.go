@@ -0,0 +1,96 @@
+package enflag
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegisterResolver(t *testing.T) {
+	var errs []string
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		errs = append(errs, envName+": "+errors.Unwrap(err).Error())
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	params := map[string]string{"/prod/db/password": "qwerty", "/prod/db/port": "5432"}
+	RegisterResolver("ssm", func(ref string) (string, error) {
+		v, ok := params[ref]
+		if !ok {
+			return "", errors.New("parameter not found")
+		}
+		return v, nil
+	})
+	defer RegisterResolver("ssm", nil)
+
+	os.Setenv("RESOLVE_PASSWORD", "ssm:/prod/db/password")
+	os.Setenv("RESOLVE_PORT", "ssm:/prod/db/port")
+	os.Setenv("RESOLVE_MISSING", "ssm:/prod/db/missing")
+	os.Setenv("RESOLVE_PLAIN", "postgres://localhost")
+	os.Setenv("RESOLVE_OFF", "ssm:/prod/db/password")
+	os.Args = []string{"cmd", "-resolve-flag", "ssm:/prod/db/password"}
+
+	var password, missing, plain, off, fl string
+	var port int
+	Var(&password).Resolve().BindEnv("RESOLVE_PASSWORD")
+	Var(&port).From(Template().Resolve()).BindEnv("RESOLVE_PORT")
+	Var(&missing).Resolve().WithDefault("none").BindEnv("RESOLVE_MISSING")
+	Var(&plain).Resolve().BindEnv("RESOLVE_PLAIN")
+	Var(&off).BindEnv("RESOLVE_OFF")
+	Var(&fl).Resolve().BindFlag("resolve-flag")
+	Parse()
+
+	checkVal(t, "qwerty", password)
+	checkVal(t, 5432, port)
+	checkVal(t, "none", missing)
+	checkVal(t, "postgres://localhost", plain)
+	checkVal(t, "ssm:/prod/db/password", off)
+	checkVal(t, "qwerty", fl)
+	checkSlice(t, []string{"RESOLVE_MISSING: parameter not found"}, errs)
+
+	RegisterResolver("ssm", nil)
+	resolved, _ := resolve("ssm:/prod/db/password")
+	checkVal(t, "ssm:/prod/db/password", resolved)
+}
+
+func TestResolveFile(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("RESOLVE_FILE_OFF", "file:///etc/hostname")
+	var off string
+	Var(&off).Resolve().BindEnv("RESOLVE_FILE_OFF")
+	Parse()
+	checkVal(t, "file:///etc/hostname", off)
+
+	reset()
+	RegisterResolver("file", ResolveFile)
+	defer RegisterResolver("file", nil)
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("RESOLVE_FILE", "file://"+path)
+	os.Setenv("RESOLVE_FILE_LOCALHOST", "file://localhost"+path)
+	os.Setenv("RESOLVE_FILE_HOST", "file://example.com"+path)
+	os.Setenv("RESOLVE_FILE_OPAQUE", "file:test.db?cache=shared")
+	os.Setenv("RESOLVE_FILE_UNMARKED", "file://"+path)
+
+	var token, localhost, host, opaque, unmarked string
+	Var(&token).Resolve().BindEnv("RESOLVE_FILE")
+	Var(&localhost).Resolve().BindEnv("RESOLVE_FILE_LOCALHOST")
+	Var(&host).Resolve().WithDefault("remote").BindEnv("RESOLVE_FILE_HOST")
+	Var(&opaque).Resolve().BindEnv("RESOLVE_FILE_OPAQUE")
+	Var(&unmarked).BindEnv("RESOLVE_FILE_UNMARKED")
+	Parse()
+
+	checkVal(t, "secret", token)
+	checkVal(t, "secret", localhost)
+	checkVal(t, "remote", host)
+	checkVal(t, "file:test.db?cache=shared", opaque)
+	checkVal(t, "file://"+path, unmarked)
+}
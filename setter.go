@@ -0,0 +1,35 @@
+package enflag
+
+// VarSetter creates a new Binding which delivers the resolved value to set
+// instead of storing it in a variable, e.g. to a method or an atomic wrapper:
+//
+//	var level slog.LevelVar
+//	enflag.VarSetter(level.Set).WithDefault(slog.LevelInfo).Bind("LOG_LEVEL", "log-level")
+//
+// set is called by Parse with the resolved value, which may be the default,
+// before the OnParsed hooks. It is called again when Watch reloads a changed
+// value, and when a Snapshot is restored.
+func VarSetter[T builtin](set func(T)) *Binding[T] {
+	b := Var(new(T))
+	b.setter = func() { set(*b.p) }
+
+	return b
+}
+
+// BindSetter is a shorthand for VarSetter(set).WithFlagUsage(flagUsage).Bind(envName, flagName).
+// Only the first element of flagUsage will be used if provided.
+func BindSetter[T builtin](set func(T), envName string, flagName string, flagUsage ...string) {
+	v := VarSetter(set)
+	if len(flagUsage) > 0 {
+		v = v.WithFlagUsage(flagUsage[0])
+	}
+
+	v.Bind(envName, flagName)
+}
+
+// deliver passes the value to the setter of the binding, if any.
+func (b *binding) deliver() {
+	if b.setter != nil {
+		b.setter()
+	}
+}
@@ -0,0 +1,42 @@
+package enflag
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVarSetter(t *testing.T) {
+	reset()
+
+	os.Setenv("SETTER_LEVEL", "3")
+	defer os.Unsetenv("SETTER_LEVEL")
+	os.Args = []string{"cmd", "-setter-timeout", "5s"}
+
+	var levels []int
+	var timeout int64
+	var host string
+	VarSetter(func(v int) { levels = append(levels, v) }).WithDefault(1).Bind("SETTER_LEVEL", "setter-level")
+	BindSetter(func(v time.Duration) { atomic.StoreInt64(&timeout, int64(v)) }, "SETTER_TIMEOUT", "setter-timeout", "timeout")
+	VarSetter(func(v string) { host = v }).WithDefault("localhost").BindEnv("SETTER_HOST")
+
+	var atHook []int
+	OnParsed(func() error {
+		atHook = append(atHook, levels...)
+		return nil
+	})
+
+	checkSlice(t, nil, levels)
+	Parse()
+
+	checkSlice(t, []int{3}, levels)
+	checkSlice(t, []int{3}, atHook)
+	checkVal(t, 5*time.Second, time.Duration(atomic.LoadInt64(&timeout)))
+	checkVal(t, "localhost", host)
+	checkVal(t, SourceEnv, Source("SETTER_LEVEL"))
+
+	s := TakeSnapshot()
+	s.Restore()
+	checkSlice(t, []int{3, 3}, levels)
+}
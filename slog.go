@@ -0,0 +1,63 @@
+//go:build go1.21
+
+package enflag
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger enables logging of the resolved configuration: Parse logs every
+// binding with its names, source and value at the info level, in the order
+// the bindings were created. Values of sensitive bindings are replaced
+// with "***". Logging is disabled if Logger is nil, which is the default.
+//
+// Logger is only available with Go 1.21+.
+var Logger *slog.Logger
+
+func logResolved(bindings []*binding) {
+	if Logger == nil {
+		return
+	}
+
+	for _, b := range bindings {
+		attrs := make([]slog.Attr, 0, 4)
+		if b.envName != "" {
+			attrs = append(attrs, slog.String("env", b.envName))
+		}
+		if b.flagName != "" {
+			attrs = append(attrs, slog.String("flag", b.flagName))
+		}
+		attrs = append(attrs,
+			slog.String("source", b.source.String()),
+			slog.Any("value", b.dumpValue()),
+		)
+
+		Logger.LogAttrs(context.Background(), slog.LevelInfo, "enflag: resolved", attrs...)
+	}
+}
+
+// bindVersioned binds types that are available only in newer Go versions.
+func bindVersioned(b *binding, p any) bool {
+	switch ptr := p.(type) {
+	case *slog.Level:
+		handleVar(b, ptr, parseSlogLevel)
+		return true
+	}
+
+	return false
+}
+
+func parseSlogLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	err := l.UnmarshalText([]byte(s))
+	return l, err
+}
+
+// VarLevel creates a new Binding for the given pointer p to a slog.Level.
+// Levels are parsed by slog.Level.UnmarshalText, e.g. "info" or "debug-2".
+//
+// VarLevel is only available with Go 1.21+.
+func VarLevel(p *slog.Level) *Binding[slog.Level] {
+	return newBinding(p)
+}
@@ -0,0 +1,9 @@
+//go:build !go1.21
+
+package enflag
+
+func bindVersioned(b *binding, p any) bool {
+	return false
+}
+
+func logResolved(bindings []*binding) {}
@@ -0,0 +1,70 @@
+//go:build go1.21
+
+package enflag
+
+import (
+	"bytes"
+	"flag"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSlogLevel(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("SLOG_LEVEL", "debug")
+	os.Setenv("SLOG_LEVEL_VAR", "WARN")
+	os.Setenv("SLOG_INVALID", "verbose")
+
+	var level slog.Level
+	var levelVar slog.LevelVar
+	var offset slog.Level
+	var invalid slog.Level
+
+	VarLevel(&level).BindEnv("SLOG_LEVEL")
+	VarText(&levelVar).BindEnv("SLOG_LEVEL_VAR")
+	VarLevel(&offset).BindFlag("slog-offset")
+	VarLevel(&invalid).WithDefault(slog.LevelError).BindEnv("SLOG_INVALID")
+
+	flag.Set("slog-offset", "info+2")
+	Parse()
+
+	checkVal(t, slog.LevelDebug, level)
+	checkVal(t, slog.LevelWarn, levelVar.Level())
+	checkVal(t, slog.LevelInfo+2, offset)
+	checkVal(t, slog.LevelError, invalid)
+}
+
+func TestLogger(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	var buf bytes.Buffer
+	Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	defer func() { Logger = nil }()
+
+	os.Setenv("LOGGER_PASSWORD", "qwerty")
+	os.Args = []string{"cmd", "-logger-timeout", "5s"}
+
+	var host, password string
+	var timeout time.Duration
+	Var(&host).WithDefault("localhost").BindEnv("LOGGER_HOST")
+	Var(&password).Sensitive().BindEnv("LOGGER_PASSWORD")
+	Var(&timeout).Bind("LOGGER_TIMEOUT", "logger-timeout")
+	Parse()
+
+	want := "level=INFO msg=\"enflag: resolved\" env=LOGGER_HOST source=default value=localhost\n" +
+		"level=INFO msg=\"enflag: resolved\" env=LOGGER_PASSWORD source=env value=***\n" +
+		"level=INFO msg=\"enflag: resolved\" env=LOGGER_TIMEOUT flag=logger-timeout source=flag value=5s\n"
+	checkVal(t, want, buf.String())
+}
@@ -0,0 +1,42 @@
+package enflag
+
+// Snapshot holds the values and sources of the bindings at the time
+// it was taken, see TakeSnapshot.
+type Snapshot struct {
+	entries []snapshotEntry
+}
+
+type snapshotEntry struct {
+	b      *binding
+	value  any
+	source ValueSource
+}
+
+// TakeSnapshot returns the current values of all registered bindings,
+// e.g. to override the configuration for a dry run and restore it later:
+//
+//	s := enflag.TakeSnapshot()
+//	defer s.Restore()
+//
+// Values are copied shallowly: slices and maps modified in place
+// are not restored.
+func TakeSnapshot() Snapshot {
+	bindings := registered()
+	entries := make([]snapshotEntry, len(bindings))
+	for i, b := range bindings {
+		entries[i] = snapshotEntry{b: b, value: b.value(), source: b.source}
+	}
+
+	return Snapshot{entries: entries}
+}
+
+// Restore assigns the values and sources of the snapshot back to the bound
+// variables, and to the setters of VarSetter bindings. Bindings created after
+// the snapshot was taken are not changed.
+func (s Snapshot) Restore() {
+	for _, e := range s.entries {
+		e.b.restore(e.value)
+		e.b.source = e.source
+		e.b.deliver()
+	}
+}
@@ -0,0 +1,39 @@
+package enflag
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	os.Setenv("SNAPSHOT_HOST", "db.local")
+
+	var host string
+	var timeout time.Duration
+	var tags []string
+	Var(&host).BindEnv("SNAPSHOT_HOST")
+	Var(&timeout).WithDefault(time.Second).BindFlag("snapshot-timeout")
+	Var(&tags).WithDefault([]string{"a"}).BindEnv("SNAPSHOT_TAGS")
+	Parse()
+
+	s := TakeSnapshot()
+
+	host, timeout, tags = "localhost", time.Minute, []string{"b", "c"}
+	lookup("SNAPSHOT_HOST").source = SourceFlag
+
+	var late int
+	Var(&late).WithDefault(1).BindEnv("SNAPSHOT_LATE")
+	late = 2
+
+	s.Restore()
+
+	checkVal(t, "db.local", host)
+	checkVal(t, SourceEnv, Source("SNAPSHOT_HOST"))
+	checkVal(t, time.Second, timeout)
+	checkSlice(t, []string{"a"}, tags)
+	checkVal(t, 2, late)
+}
@@ -0,0 +1,38 @@
+package enflag
+
+// Binder is a binding which is not bound yet, a *Binding or a *CustomBinding.
+type Binder interface {
+	Bind(envName string, flagName string)
+	base() *binding
+}
+
+// Spec declares a binding for BindAll. Var carries the pointer
+// and the options of the binding, e.g. enflag.Var(&conf.Port).WithMin(1).
+type Spec struct {
+	Var   Binder
+	Env   string
+	Flag  string
+	Usage string
+}
+
+// BindAll binds the variables of the specs, allowing the declaration of
+// large configurations as data instead of one chain per setting:
+//
+//	enflag.BindAll([]enflag.Spec{
+//		{Var: enflag.Var(&conf.Host).WithDefault("localhost"), Env: "HOST", Flag: "host"},
+//		{Var: enflag.Var(&conf.Port).WithDefault(8080).WithMin(1), Env: "PORT", Flag: "port"},
+//		{Var: enflag.VarJSON(&conf.Limits), Env: "LIMITS", Usage: "rate limits"},
+//	})
+func BindAll(specs []Spec) {
+	for _, s := range specs {
+		if s.Usage != "" {
+			s.Var.base().flagUsage = s.Usage
+		}
+
+		s.Var.Bind(s.Env, s.Flag)
+	}
+}
+
+func (b *binding) base() *binding {
+	return b
+}
@@ -0,0 +1,37 @@
+package enflag
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestBindAll(t *testing.T) {
+	reset()
+
+	os.Setenv("SPEC_PORT", "9000")
+	defer os.Unsetenv("SPEC_PORT")
+	os.Args = []string{"cmd", "-spec-timeout", "5s"}
+
+	var conf struct {
+		Host    string
+		Port    int
+		Timeout time.Duration
+		Level   int
+	}
+	BindAll([]Spec{
+		{Var: Var(&conf.Host).WithDefault("localhost"), Env: "SPEC_HOST", Flag: "spec-host", Usage: "host name"},
+		{Var: Var(&conf.Port).WithDefault(8080).WithMin(1), Env: "SPEC_PORT"},
+		{Var: Var(&conf.Timeout).WithDefault(time.Second), Flag: "spec-timeout"},
+		{Var: VarFunc(&conf.Level, func(s string) (int, error) { return len(s), nil }).WithDefault(3), Env: "SPEC_LEVEL"},
+	})
+	Parse()
+
+	checkVal(t, "localhost", conf.Host)
+	checkVal(t, 9000, conf.Port)
+	checkVal(t, 5*time.Second, conf.Timeout)
+	checkVal(t, 3, conf.Level)
+
+	info, _ := Lookup("spec-host")
+	checkVal(t, "host name", info.FlagUsage)
+}
@@ -0,0 +1,86 @@
+package enflag
+
+import (
+	"strings"
+	"text/template"
+)
+
+// pendingTemplate is a templated value waiting for the other bindings
+// to be parsed.
+type pendingTemplate struct {
+	text     string
+	envName  string
+	flagName string
+}
+
+// deferTemplate stores a templated raw value of the binding to be evaluated
+// by Parse, and reports whether the value was deferred.
+func (b *binding) deferTemplate(s string, envName string, flagName string) bool {
+	if !b.templated {
+		return false
+	}
+
+	if !strings.Contains(s, "{{") {
+		// a later source overrides the pending template
+		b.pending = nil
+		return false
+	}
+
+	b.pending = &pendingTemplate{text: s, envName: envName, flagName: flagName}
+	return true
+}
+
+// evalTemplates evaluates the pending templates of the bindings in the order
+// they were bound. The data of the templates is a map of the parsed values
+// keyed by the environment variable and flag names of all bindings.
+func evalTemplates(bindings []*binding) {
+	var data map[string]any
+	for _, b := range bindings {
+		if b.evalTemplate == nil {
+			continue
+		}
+
+		pending := b.pending
+		if pending == nil && b.source == SourceDefault && b.defTemplate != "" {
+			pending = &pendingTemplate{text: b.defTemplate}
+		}
+		if pending == nil {
+			continue
+		}
+		b.pending = nil
+
+		if data == nil {
+			all := registered()
+			data = make(map[string]any, 2*len(all))
+			for _, other := range all {
+				other.addTemplateData(data)
+			}
+		}
+
+		b.evalTemplate(pending, data)
+		b.addTemplateData(data)
+	}
+}
+
+func (b *binding) addTemplateData(data map[string]any) {
+	if b.envName != "" {
+		data[b.envName] = b.value()
+	}
+	if b.flagName != "" {
+		data[b.flagName] = b.value()
+	}
+}
+
+func execTemplate(text string, data map[string]any) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,55 @@
+package enflag
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTemplated(t *testing.T) {
+	var errs []string
+	ErrorHandlerFunc = func(err error, rawVal string, target any, envName string, flagName string) {
+		errs = append(errs, envName)
+	}
+	defer func() { ErrorHandlerFunc = OnErrorIgnore }()
+	reset()
+
+	os.Setenv("TMPL_HOST", "localhost")
+	os.Setenv("TMPL_PORT", "8080")
+	os.Setenv("TMPL_URL", "http://{{.TMPL_HOST}}:{{.TMPL_PORT}}/{{index . \"tmpl-path\"}}")
+	os.Setenv("TMPL_TIMEOUT", "{{.TMPL_BASE_TIMEOUT}}")
+	os.Setenv("TMPL_OVERRIDDEN", "{{.TMPL_HOST}}")
+	os.Setenv("TMPL_RAW", "{{.TMPL_HOST}}")
+	os.Setenv("TMPL_INVALID", "{{.TMPL_MISSING}}")
+	os.Args = []string{"cmd", "-tmpl-path", "api", "-tmpl-overridden", "flag"}
+
+	var host, path, url, listen, overridden, raw, invalid, setListen string
+	var port int
+	var timeout, baseTimeout time.Duration
+	Var(&host).BindEnv("TMPL_HOST")
+	Var(&port).BindEnv("TMPL_PORT")
+	Var(&url).Templated().BindEnv("TMPL_URL")
+	Var(&path).BindFlag("tmpl-path")
+	Var(&timeout).Templated().BindEnv("TMPL_TIMEOUT")
+	Var(&baseTimeout).WithDefault(5 * time.Second).BindEnv("TMPL_BASE_TIMEOUT")
+	Var(&overridden).Templated().Bind("TMPL_OVERRIDDEN", "tmpl-overridden")
+	Var(&raw).BindEnv("TMPL_RAW")
+	Var(&invalid).WithDefault("default").Templated().BindEnv("TMPL_INVALID")
+
+	listenB := Var(&listen).WithDefaultTemplate("{{.TMPL_HOST}}:{{.TMPL_PORT}}")
+	listenB.BindEnv("TMPL_LISTEN")
+	Var(&setListen).WithDefaultTemplate("{{.TMPL_HOST}}").BindFlag("tmpl-set-listen")
+	os.Args = append(os.Args, "-tmpl-set-listen", "0.0.0.0:80")
+	Parse()
+
+	checkVal(t, "http://localhost:8080/api", url)
+	checkVal(t, 5*time.Second, timeout)
+	checkVal(t, "flag", overridden)
+	checkVal(t, "{{.TMPL_HOST}}", raw)
+	checkVal(t, "default", invalid)
+	checkVal(t, "localhost:8080", listen)
+	checkVal(t, SourceDefault, listenB.Source())
+	checkVal(t, "0.0.0.0:80", setListen)
+	checkSlice(t, []string{"TMPL_INVALID"}, errs)
+	checkVal(t, SourceEnv, Source("TMPL_URL"))
+}
@@ -0,0 +1,166 @@
+package enflag
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// CertificateBinding loads a TLS certificate and its private key into
+// a tls.Certificate. It should be created using the VarCertificate function
+// and finalized by calling Bind().
+type CertificateBinding struct {
+	p *tls.Certificate
+
+	certUsage string
+	keyUsage  string
+}
+
+// VarCertificate creates a new CertificateBinding for the given pointer p.
+// Both the certificate and the key are accepted as file paths or
+// as inline PEM data.
+//
+// Example usage:
+//
+//	var cert tls.Certificate
+//	VarCertificate(&cert).Bind("TLS_CERT", "tls-cert", "TLS_KEY", "tls-key")
+func VarCertificate(p *tls.Certificate) *CertificateBinding {
+	return &CertificateBinding{
+		p:         p,
+		certUsage: "TLS certificate file or PEM data",
+		keyUsage:  "TLS private key file or PEM data",
+	}
+}
+
+// WithFlagUsage sets the usage messages of the certificate and the key flags.
+func (b *CertificateBinding) WithFlagUsage(certUsage string, keyUsage string) *CertificateBinding {
+	b.certUsage, b.keyUsage = certUsage, keyUsage
+	return b
+}
+
+// Bind binds the certificate and the key to the given environment variables
+// and flags. Empty names are skipped. The pair is loaded by Parse,
+// and an error is reported if only one of them is set or they don't match.
+func (b *CertificateBinding) Bind(certEnv string, certFlag string, keyEnv string, keyFlag string) {
+	var cert, key string
+	Var(&cert).WithFlagUsage(b.certUsage).Bind(certEnv, certFlag)
+	keyBinding := Var(&key).WithFlagUsage(b.keyUsage).Sensitive()
+	keyBinding.Bind(keyEnv, keyFlag)
+
+	addParseHook(func() {
+		if cert == "" && key == "" {
+			return
+		}
+		if cert == "" || key == "" {
+			handleError(nil, errors.New("both certificate and key must be set"), b.p, "", certEnv, certFlag)
+			return
+		}
+
+		certPEM, err := readPEM(cert)
+		if err != nil {
+			handleError(nil, err, b.p, "", certEnv, certFlag)
+			return
+		}
+
+		// errors of the key are handled by its binding, so they are redacted
+		keyPEM, err := readPEM(key)
+		if err != nil {
+			handleError(&keyBinding.binding, err, b.p, "", keyEnv, keyFlag)
+			return
+		}
+
+		pair, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			handleError(nil, err, b.p, "", certEnv, certFlag)
+			return
+		}
+
+		*b.p = pair
+	})
+}
+
+// parseCertPool creates a new certificate pool from a PEM bundle
+// or a path to a PEM file. System roots are not included.
+func parseCertPool(s string) (*x509.CertPool, error) {
+	data, err := readPEM(s)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("no certificates found")
+	}
+
+	return pool, nil
+}
+
+// ParsePrivateKey parses a PEM-encoded RSA, ECDSA or Ed25519 private key
+// in PKCS #1, SEC 1 or PKCS #8 form. The PEM data may be base64-encoded
+// as a whole, or s may be a path to a PEM file. It can be used with VarFunc:
+//
+//	var key crypto.Signer
+//	VarFunc(&key, ParsePrivateKey).Sensitive().BindEnv("JWT_SIGNING_KEY")
+func ParsePrivateKey(s string) (crypto.Signer, error) {
+	if !strings.Contains(s, "-----BEGIN ") {
+		if data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s)); err == nil {
+			if strings.Contains(string(data), "-----BEGIN ") {
+				s = string(data)
+			}
+		}
+	}
+
+	data, err := readPEM(s)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unsupported private key format")
+}
+
+// readPEM returns s if it contains PEM data, otherwise s is treated
+// as a file path. Errors never contain s, which may be an inline key.
+func readPEM(s string) ([]byte, error) {
+	if strings.Contains(s, "-----BEGIN ") {
+		return []byte(s), nil
+	}
+
+	data, err := os.ReadFile(s)
+	if err != nil {
+		var pErr *fs.PathError
+		if errors.As(err, &pErr) {
+			err = pErr.Err
+		}
+		return nil, fmt.Errorf("neither PEM data nor a readable file: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,61 @@
+package enflag
+
+import "testing"
+
+func TestSemVerCompare(t *testing.T) {
+	// ordered by precedence, see https://semver.org/#spec-item-11
+	versions := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.2.0",
+		"2.0.0",
+	}
+
+	for i := range versions {
+		for j := range versions {
+			a, err := parseSemVer(versions[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := parseSemVer(versions[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := a.Compare(b), compareOrdered(i, j); got != want {
+				t.Errorf("compare %s and %s: expected %d, got %d", a, b, want, got)
+			}
+		}
+	}
+
+	a, _ := parseSemVer("1.0.0+build.1")
+	b, _ := parseSemVer("1.0.0+build.2")
+	checkVal(t, 0, a.Compare(b))
+	checkVal(t, false, a.Less(b))
+}
+
+func TestParseSemVer(t *testing.T) {
+	for _, s := range []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.2.3-", "1.2.3-01", "1.2.3+", "1.2.3-a..b", "1.2.x", "1.2.3-a_b"} {
+		if _, err := parseSemVer(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestParseCronExpr(t *testing.T) {
+	for _, s := range []string{
+		"", "* * * *", "* * * * * *", "*/0 * * * *", "5-1 * * * *", "* 24 * * *",
+		"* * 0 * *", "* * * 13 *", "* * * * 8", "* * * * MON-XYZ", "@every", "a * * * *",
+	} {
+		if _, err := parseCronExpr(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
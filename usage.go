@@ -0,0 +1,89 @@
+package enflag
+
+import (
+	"flag"
+	"fmt"
+)
+
+// prevFlagUsage is flag.Usage before installGroupedUsage replaced it,
+// restored by Reset.
+var prevFlagUsage func()
+
+// groupUsageSet is the flag set whose Usage is replaced by groupedUsage.
+var groupUsageSet *flag.FlagSet
+
+// installGroupedUsage replaces the Usage function of the flag set,
+// or flag.Usage for flag.CommandLine, the first time a binding with a group
+// is registered. A custom Usage function can still be assigned after
+// the bindings are created.
+func installGroupedUsage() {
+	fs := flagSet()
+	if groupUsageSet == fs {
+		return
+	}
+	groupUsageSet = fs
+
+	usage := func() { printGroupedUsage(fs) }
+	if fs == flag.CommandLine {
+		prevFlagUsage = flag.Usage
+		flag.Usage = usage
+	} else {
+		fs.Usage = usage
+	}
+}
+
+// printGroupedUsage prints the usage message like flag.PrintDefaults,
+// but flags are split into sections by their groups. Flags without
+// a group are printed first, sorted by name. Groups and their flags
+// are ordered by weight, see WithWeight.
+func printGroupedUsage(fs *flag.FlagSet) {
+	out := fs.Output()
+	if fs.Name() == "" {
+		fmt.Fprintf(out, "Usage:\n")
+	} else {
+		fmt.Fprintf(out, "Usage of %s:\n", fs.Name())
+	}
+
+	grouped := make(map[string]bool)
+	var order []string
+	names := make(map[string][]string)
+	for _, b := range docOrder() {
+		if b.flagName == "" || b.group == "" || grouped[b.flagName] {
+			continue
+		}
+		grouped[b.flagName] = true
+
+		if _, ok := names[b.group]; !ok {
+			order = append(order, b.group)
+		}
+		names[b.group] = append(names[b.group], b.flagName)
+	}
+
+	section := flag.NewFlagSet("", flag.ContinueOnError)
+	section.SetOutput(out)
+	fs.VisitAll(func(f *flag.Flag) {
+		if !grouped[f.Name] {
+			copyFlag(section, f)
+		}
+	})
+	section.PrintDefaults()
+
+	// flag sets print flags sorted by name, so the flags of the groups
+	// are printed one by one to keep their order
+	for _, g := range order {
+		fmt.Fprintf(out, "\n%s:\n", g)
+		for _, name := range names[g] {
+			if f := fs.Lookup(name); f != nil {
+				section := flag.NewFlagSet(g, flag.ContinueOnError)
+				section.SetOutput(out)
+				copyFlag(section, f)
+				section.PrintDefaults()
+			}
+		}
+	}
+}
+
+func copyFlag(fs *flag.FlagSet, f *flag.Flag) {
+	fs.Var(f.Value, f.Name, f.Usage)
+	fs.Lookup(f.Name).DefValue = f.DefValue
+}
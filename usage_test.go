@@ -0,0 +1,120 @@
+package enflag
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestGroupedUsage(t *testing.T) {
+	reset()
+
+	var buf bytes.Buffer
+	flag.CommandLine.SetOutput(&buf)
+
+	var host, dbHost, dbUser string
+	var port int
+	var verbose bool
+
+	flag.BoolVar(&verbose, "verbose", false, "verbose output")
+	Var(&host).WithDefault("localhost").WithFlagUsage("listen host").BindFlag("host")
+	Var(&dbHost).WithGroup("Database").WithFlagUsage("database host").BindFlag("db-host")
+	Var(&port).WithGroup("HTTP").WithFlagUsage("listen port").BindFlag("port")
+	Var(&dbUser).WithGroup("Database").BindFlag("db-user")
+
+	flag.Usage()
+
+	want := `Usage of cmd:
+  -host value
+    	listen host
+  -verbose
+    	verbose output
+
+Database:
+  -db-host value
+    	database host
+  -db-user value
+    	
+
+HTTP:
+  -port value
+    	listen port
+`
+	checkVal(t, want, buf.String())
+}
+
+func TestGroupedUsageFlagSet(t *testing.T) {
+	reset()
+
+	fs := flag.NewFlagSet("custom", flag.ContinueOnError)
+	FlagSet = fs
+	defer func() { FlagSet = nil }()
+
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+
+	var port int
+	Var(&port).WithGroup("HTTP").BindFlag("port")
+
+	if err := fs.Parse([]string{"-unknown"}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	want := `flag provided but not defined: -unknown
+Usage of custom:
+
+HTTP:
+  -port value
+    	
+`
+	checkVal(t, want, buf.String())
+}
+
+func TestGroupedUsageWeight(t *testing.T) {
+	reset()
+
+	var buf bytes.Buffer
+	flag.CommandLine.SetOutput(&buf)
+
+	var host, dbHost, dbUser, logLevel string
+	var port int
+	Var(&logLevel).WithGroup("Logging").WithWeight(10).BindFlag("log-level")
+	Var(&dbUser).WithGroup("Database").WithWeight(2).BindFlag("db-user")
+	Var(&dbHost).WithGroup("Database").WithWeight(1).BindFlag("db-host")
+	Var(&port).WithGroup("HTTP").WithWeight(-1).BindFlag("port")
+	Var(&host).WithGroup("HTTP").BindFlag("host")
+
+	flag.Usage()
+
+	want := `Usage of cmd:
+
+HTTP:
+  -port value
+    	
+  -host value
+    	
+
+Database:
+  -db-host value
+    	
+  -db-user value
+    	
+
+Logging:
+  -log-level value
+    	
+`
+	checkVal(t, want, buf.String())
+
+	buf.Reset()
+	if err := GenerateMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "`-host` | `string` |  |  |\n|  | `-db-host`") {
+		t.Errorf("want bindings in weight order, got %s", buf.String())
+	}
+
+	info, _ := Lookup("log-level")
+	checkVal(t, 10, info.Weight)
+}
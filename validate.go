@@ -0,0 +1,300 @@
+package enflag
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+func (b *binding) validate(v any) error {
+	for _, f := range b.validators {
+		if err := f(v); err != nil {
+			return &validationError{err: err}
+		}
+	}
+
+	return nil
+}
+
+func (b *binding) addAllowed(vals []string) {
+	if len(b.allowed) == 0 {
+		b.validators = append(b.validators, func(v any) error {
+		values:
+			for _, s := range allowedValues(v) {
+				for _, a := range b.allowed {
+					if s == a {
+						continue values
+					}
+				}
+
+				return constraintError("must be one of: " + strings.Join(b.allowed, ", "))
+			}
+
+			return nil
+		})
+	}
+
+	b.allowed = append(b.allowed, vals...)
+}
+
+func formatAll[T any](vals []T) []string {
+	res := make([]string, 0, len(vals))
+	for _, v := range vals {
+		res = append(res, allowedValues(v)...)
+	}
+	return res
+}
+
+// allowedValues returns the string representations of v compared by WithAllowed.
+// Pointers are dereferenced, unless they implement fmt.Stringer, and slices
+// are split into their elements, so each element must be allowed.
+// Byte slices like net.IP are compared as a whole.
+func allowedValues(v any) []string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() && !rv.Type().Implements(stringerType) {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		res := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			res = append(res, allowedValues(rv.Index(i).Interface())...)
+		}
+		return res
+	}
+
+	if !rv.IsValid() {
+		return []string{fmt.Sprint(v)}
+	}
+	return []string{fmt.Sprint(rv.Interface())}
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+func checkNonEmpty[T any](b *binding, ptr *T) {
+	if isEmpty(reflect.ValueOf(ptr).Elem()) {
+		err := &MissingError{Env: b.envName, Flag: b.flagName}
+		handleError(b, err, ptr, "", b.envName, b.flagName)
+	}
+}
+
+// isEmpty reports whether v is the zero value, an empty slice or map,
+// or a pointer to an empty value.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Pointer:
+		return v.IsNil() || isEmpty(v.Elem())
+	}
+
+	return v.IsZero()
+}
+
+// checkPattern panics if the pattern constraint is not supported
+// for the type of v, see checkComparable.
+func checkPattern(v any) {
+	switch v.(type) {
+	case string, []string:
+		return
+	}
+
+	panic(fmt.Sprintf("enflag: WithPattern is not supported for type %T", v))
+}
+
+func matchPattern(re *regexp.Regexp, v any) error {
+	var vals []string
+	switch v := v.(type) {
+	case string:
+		vals = []string{v}
+	case []string:
+		vals = v
+	default:
+		return fmt.Errorf("pattern constraint is not supported for type %T", v)
+	}
+
+	for _, s := range vals {
+		if !re.MatchString(s) {
+			return constraintError(fmt.Sprintf("must match pattern %q", re.String()))
+		}
+	}
+
+	return nil
+}
+
+// ExistingFile checks that path exists and is not a directory.
+// It can be used as a validation function for string bindings:
+//
+//	Var(&certPath).WithValidate(enflag.ExistingFile).BindEnv("CERT_PATH")
+func ExistingFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("file %q is not accessible: %w", path, err)
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("%q is a directory, not a file", path)
+	}
+
+	return nil
+}
+
+// ExistingDir checks that path exists and is a directory.
+// It can be used as a validation function for string bindings.
+func ExistingDir(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("directory %q is not accessible: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%q is not a directory", path)
+	}
+
+	return nil
+}
+
+// WritableDir checks that path is an existing directory the process
+// can create files in. It can be used as a validation function
+// for string bindings.
+func WritableDir(path string) error {
+	if err := ExistingDir(path); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(path, ".enflag-*")
+	if err != nil {
+		return fmt.Errorf("directory %q is not writable: %w", path, err)
+	}
+	f.Close()
+
+	return os.Remove(f.Name())
+}
+
+type ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64 | ~string
+}
+
+// valueRange holds optional bounds of a numeric binding.
+type valueRange struct {
+	min any
+	max any
+}
+
+func (b *binding) setMin(v any) {
+	checkComparable("WithMin", v)
+	b.addRange()
+	b.rng.min = v
+}
+
+func (b *binding) setMax(v any) {
+	checkComparable("WithMax", v)
+	b.addRange()
+	b.rng.max = v
+}
+
+// checkComparable panics if the range constraint is not supported
+// for the type of v, so the misuse is reported when the binding is built.
+func checkComparable(method string, v any) {
+	if _, ok := compare(v, v); !ok {
+		panic(fmt.Sprintf("enflag: %s is not supported for type %T", method, v))
+	}
+}
+
+func (b *binding) addRange() {
+	if b.rng != nil {
+		return
+	}
+
+	b.rng = &valueRange{}
+	b.validators = append(b.validators, func(v any) error {
+		return b.rng.check(v)
+	})
+}
+
+func (r *valueRange) check(v any) error {
+	okMin, okMax := true, true
+	if r.min != nil {
+		c, ok := compare(v, r.min)
+		if !ok {
+			return fmt.Errorf("range constraint is not supported for type %T", v)
+		}
+		okMin = c >= 0
+	}
+	if r.max != nil {
+		c, ok := compare(v, r.max)
+		if !ok {
+			return fmt.Errorf("range constraint is not supported for type %T", v)
+		}
+		okMax = c <= 0
+	}
+
+	if okMin && okMax {
+		return nil
+	}
+
+	switch {
+	case r.min != nil && r.max != nil:
+		return constraintError(fmt.Sprintf("must be in range [%v, %v]", r.min, r.max))
+	case r.min != nil:
+		return constraintError(fmt.Sprintf("must be greater than or equal to %v", r.min))
+	default:
+		return constraintError(fmt.Sprintf("must be less than or equal to %v", r.max))
+	}
+}
+
+// compare compares two values of the same numeric or SemVer type.
+// The second result is false if the type is not supported.
+func compare(a, b any) (int, bool) {
+	switch a := a.(type) {
+	case int:
+		return compareOrdered(a, b.(int)), true
+	case int8:
+		return compareOrdered(a, b.(int8)), true
+	case int16:
+		return compareOrdered(a, b.(int16)), true
+	case int32:
+		return compareOrdered(a, b.(int32)), true
+	case int64:
+		return compareOrdered(a, b.(int64)), true
+	case uint:
+		return compareOrdered(a, b.(uint)), true
+	case uint8:
+		return compareOrdered(a, b.(uint8)), true
+	case uint16:
+		return compareOrdered(a, b.(uint16)), true
+	case uint32:
+		return compareOrdered(a, b.(uint32)), true
+	case uint64:
+		return compareOrdered(a, b.(uint64)), true
+	case float32:
+		return compareOrdered(a, b.(float32)), true
+	case float64:
+		return compareOrdered(a, b.(float64)), true
+	case time.Duration:
+		return compareOrdered(a, b.(time.Duration)), true
+	case ByteSize:
+		return compareOrdered(a, b.(ByteSize)), true
+	case SemVer:
+		return a.Compare(b.(SemVer)), true
+	case Counter:
+		return compareOrdered(a, b.(Counter)), true
+	}
+
+	return 0, false
+}
+
+func compareOrdered[T ordered](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,96 @@
+package enflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VaultSecret is a secret read from HashiCorp Vault.
+type VaultSecret struct {
+	Data map[string]any
+
+	// lease metadata, e.g. for renewal of dynamic secrets
+	LeaseID       string
+	LeaseDuration time.Duration
+	Renewable     bool
+}
+
+// VaultClient reads secrets from HashiCorp Vault, e.g. a wrapper of
+// the Logical client of github.com/hashicorp/vault/api.
+type VaultClient interface {
+	ReadSecret(path string) (*VaultSecret, error)
+}
+
+// Vault resolves references like "secret/data/app#password" to the values
+// of the keys of Vault secrets. Each secret is read once.
+//
+//	vault := enflag.NewVault(client)
+//	enflag.RegisterResolver("vault", vault.Resolve)
+//	enflag.Var(&conf.DBPassword).Resolve().BindEnv("DB_PASSWORD") // DB_PASSWORD=vault:secret/data/app#db_password
+type Vault struct {
+	client  VaultClient
+	secrets map[string]*VaultSecret
+}
+
+// NewVault returns a Vault which reads secrets with the client.
+func NewVault(client VaultClient) *Vault {
+	return &Vault{client: client, secrets: make(map[string]*VaultSecret)}
+}
+
+// Resolve returns the value of the key after '#' in the secret at the path
+// before it. Data of KV version 2 secrets is unwrapped. Values other than
+// strings are returned as JSON, and so is the whole data if there is no key.
+func (v *Vault) Resolve(ref string) (string, error) {
+	path, key, _ := strings.Cut(ref, "#")
+
+	secret, ok := v.secrets[path]
+	if !ok {
+		var err error
+		secret, err = v.client.ReadSecret(path)
+		if err != nil {
+			return "", err
+		}
+		if secret == nil {
+			return "", fmt.Errorf("vault secret %q not found", path)
+		}
+		v.secrets[path] = secret
+	}
+
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	var val any = data
+	if key != "" {
+		if val, ok = data[key]; !ok {
+			return "", fmt.Errorf("key %q not found in vault secret %q", key, path)
+		}
+	}
+
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+
+	res, err := json.Marshal(val)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+// Secrets returns the secrets read by Resolve keyed by path,
+// e.g. to renew their leases.
+func (v *Vault) Secrets() map[string]*VaultSecret {
+	res := make(map[string]*VaultSecret, len(v.secrets))
+	for path, secret := range v.secrets {
+		res[path] = secret
+	}
+
+	return res
+}
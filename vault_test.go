@@ -0,0 +1,81 @@
+package enflag
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+type fakeVaultClient struct {
+	secrets map[string]*VaultSecret
+	reads   int
+}
+
+func (c *fakeVaultClient) ReadSecret(path string) (*VaultSecret, error) {
+	c.reads++
+	if path == "secret/broken" {
+		return nil, errors.New("permission denied")
+	}
+
+	return c.secrets[path], nil
+}
+
+func TestVault(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	client := &fakeVaultClient{secrets: map[string]*VaultSecret{
+		"secret/data/app": {
+			Data: map[string]any{
+				"data":     map[string]any{"password": "qwerty", "workers": 8.0},
+				"metadata": map[string]any{"version": 3.0},
+			},
+		},
+		"database/creds/app": {
+			Data:          map[string]any{"username": "v-app", "password": "generated"},
+			LeaseID:       "database/creds/app/abc",
+			LeaseDuration: time.Hour,
+			Renewable:     true,
+		},
+	}}
+	vault := NewVault(client)
+	RegisterResolver("vault", vault.Resolve)
+	defer RegisterResolver("vault", nil)
+
+	os.Setenv("VAULT_PASSWORD", "vault:secret/data/app#password")
+	os.Setenv("VAULT_WORKERS", "vault:secret/data/app#workers")
+	os.Setenv("VAULT_DB_USER", "vault:database/creds/app#username")
+	os.Setenv("VAULT_APP", "vault:secret/data/app")
+	os.Setenv("VAULT_MISSING_KEY", "vault:secret/data/app#token")
+	os.Setenv("VAULT_MISSING", "vault:secret/data/missing#token")
+	os.Setenv("VAULT_BROKEN", "vault:secret/broken#token")
+
+	var password, user, missingKey, missing, broken string
+	var workers int
+	var app map[string]any
+	Var(&password).Resolve().BindEnv("VAULT_PASSWORD")
+	Var(&workers).Resolve().BindEnv("VAULT_WORKERS")
+	Var(&user).Resolve().BindEnv("VAULT_DB_USER")
+	VarJSON(&app).Resolve().BindEnv("VAULT_APP")
+	Var(&missingKey).Resolve().WithDefault("a").BindEnv("VAULT_MISSING_KEY")
+	Var(&missing).Resolve().WithDefault("b").BindEnv("VAULT_MISSING")
+	Var(&broken).Resolve().WithDefault("c").BindEnv("VAULT_BROKEN")
+	Parse()
+
+	checkVal(t, "qwerty", password)
+	checkVal(t, 8, workers)
+	checkVal(t, "v-app", user)
+	checkVal(t, "qwerty", app["password"].(string))
+	checkVal(t, "a", missingKey)
+	checkVal(t, "b", missing)
+	checkVal(t, "c", broken)
+
+	// successfully read secrets are cached
+	checkVal(t, 4, client.reads)
+
+	secrets := vault.Secrets()
+	checkVal(t, 2, len(secrets))
+	checkVal(t, "database/creds/app/abc", secrets["database/creds/app"].LeaseID)
+	checkVal(t, time.Hour, secrets["database/creds/app"].LeaseDuration)
+}
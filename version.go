@@ -0,0 +1,60 @@
+package enflag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+)
+
+// versionOutput is where the version information is printed.
+var versionOutput io.Writer = os.Stdout
+
+// Version registers the -version and -V flags, which print the given version
+// along with the build information embedded by the Go toolchain, and exit
+// with status code 0. It should be called before Parse.
+// The flags are not registered if EnvOnly is set.
+//
+//	enflag.Version("1.2.3")
+func Version(version string) {
+	if EnvOnly {
+		return
+	}
+
+	v := versionFlag(version)
+	defineFlag(v, "version", "print version information and exit")
+	defineFlag(v, "V", "print version information and exit")
+}
+
+// versionFlag is a boolean-like flag.Value, so it can be used without a value.
+type versionFlag string
+
+func (v versionFlag) String() string {
+	return ""
+}
+
+func (v versionFlag) IsBoolFlag() bool {
+	return true
+}
+
+func (v versionFlag) Set(s string) error {
+	if s != "true" {
+		return nil
+	}
+
+	fmt.Fprintf(versionOutput, "%s %s\n", filepath.Base(os.Args[0]), string(v))
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(versionOutput, "  go: %s\n", info.GoVersion)
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision", "vcs.time", "vcs.modified":
+				fmt.Fprintf(versionOutput, "  %s: %s\n", s.Key, s.Value)
+			}
+		}
+	}
+
+	osExitFunc(0)
+	return nil
+}
@@ -0,0 +1,48 @@
+package enflag
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	for _, name := range []string{"-version", "-V"} {
+		t.Run(name, func(t *testing.T) {
+			reset()
+
+			var buf bytes.Buffer
+			versionOutput = &buf
+			defer func() { versionOutput = os.Stdout }()
+
+			code := -1
+			osExitFunc = func(c int) { code = c }
+			defer func() { osExitFunc = os.Exit }()
+
+			Version("1.2.3")
+			if err := flag.CommandLine.Parse([]string{name}); err != nil {
+				t.Fatal(err)
+			}
+
+			checkVal(t, 0, code)
+			checkVal(t, true, strings.HasPrefix(buf.String(), "cmd 1.2.3\n  go: go"))
+		})
+	}
+
+	t.Run("Not set", func(t *testing.T) {
+		reset()
+
+		var buf bytes.Buffer
+		versionOutput = &buf
+		defer func() { versionOutput = os.Stdout }()
+
+		Version("1.2.3")
+		if err := flag.CommandLine.Parse([]string{"-V=false"}); err != nil {
+			t.Fatal(err)
+		}
+
+		checkVal(t, "", buf.String())
+	})
+}
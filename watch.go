@@ -0,0 +1,196 @@
+package enflag
+
+import (
+	"os"
+	"reflect"
+	"time"
+)
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Interval between checks of the files, one second by default.
+	Interval time.Duration
+
+	// Debounce delays the reload until the files have not changed
+	// for the given duration, e.g. while a file is being written.
+	Debounce time.Duration
+
+	// OnReload is called with the env-variable names of the bindings
+	// whose values were changed by the reload.
+	OnReload func(names []string)
+
+	// OnError is called for new values that fail to parse or validate,
+	// the previous values are kept. Errors are printed if it is nil.
+	OnError func(err error, envName string)
+}
+
+// Watch polls the files the env values were read from: files referenced by
+// FromFile and EnvFileSuffix variables, .env files loaded by LoadEnvFile,
+// and files loaded by LoadDir. When a file changes, the affected bindings
+// are resolved again. A .env file which fails to parse is reported
+// to OnError, and its previous values are kept.
+// Values set by flags are never replaced.
+//
+// Watch should be called after Parse. Values are updated in a separate
+// goroutine without holding any lock of the package, so the bound variables,
+// and Bindings, Dump and TakeSnapshot which read them, are not safe to use
+// concurrently: they should be read in OnReload or otherwise synchronized.
+// The returned function stops watching.
+func Watch(opts WatchOptions) (stop func()) {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+
+	w := &watcher{opts: opts, states: make(map[string]fileState)}
+	w.scan()
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		w.run(done)
+	}()
+
+	return func() {
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+		<-stopped
+	}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+type watcher struct {
+	opts    WatchOptions
+	states  map[string]fileState
+	changed map[string]bool
+}
+
+func (w *watcher) run(done chan struct{}) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	var changedAt time.Time
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		if w.scan() {
+			changedAt = time.Now()
+		}
+		if len(w.changed) > 0 && time.Since(changedAt) >= w.opts.Debounce {
+			w.reload()
+		}
+	}
+}
+
+// scan updates the states of the watched files
+// and reports whether any of them has changed.
+func (w *watcher) scan() bool {
+	var changed bool
+	for _, b := range registered() {
+		if b.file == "" || b.reader == nil || b.source == SourceFlag {
+			continue
+		}
+
+		var state fileState
+		if info, err := os.Stat(b.file); err == nil {
+			state = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+
+		prev, ok := w.states[b.file]
+		w.states[b.file] = state
+		if ok && prev != state {
+			if w.changed == nil {
+				w.changed = make(map[string]bool)
+			}
+			w.changed[b.file] = true
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func (w *watcher) reload() {
+	w.reloadEnvFiles()
+	for name, path := range dirFiles {
+		if !w.changed[path] {
+			continue
+		}
+
+		if data, err := os.ReadFile(path); err == nil {
+			dirValues[name] = string(data)
+		}
+	}
+
+	var names []string
+	for _, b := range registered() {
+		if !w.changed[b.file] || b.reader == nil || b.source == SourceFlag {
+			continue
+		}
+
+		prev, handler := b.value(), b.errorHandler
+		b.errorHandler, b.reloading = w.onError, true
+		b.reload()
+		b.errorHandler, b.reloading = handler, false
+
+		if !reflect.DeepEqual(prev, b.value()) {
+			b.deliver()
+			names = append(names, b.envName)
+		}
+	}
+	w.changed = nil
+
+	if len(names) > 0 && w.opts.OnReload != nil {
+		w.opts.OnReload(names)
+	}
+}
+
+// reloadEnvFiles parses the changed .env files again and replaces
+// their values. Keys removed from a file are removed as well.
+func (w *watcher) reloadEnvFiles() {
+	files := make(map[string]map[string]string)
+	for name, path := range envFiles {
+		if !w.changed[path] {
+			continue
+		}
+
+		values, ok := files[path]
+		if !ok {
+			var err error
+			if values, err = readEnvFile(path); err != nil {
+				w.onError(err, "", nil, "", "")
+			}
+			files[path] = values
+		}
+		if values == nil {
+			continue
+		}
+
+		if v, ok := values[name]; ok {
+			envFileValues[name] = v
+		} else {
+			delete(envFileValues, name)
+			delete(envFiles, name)
+		}
+	}
+}
+
+// onError is the error handler of the bindings while they are reloaded.
+func (w *watcher) onError(err error, rawVal string, target any, envName string, flagName string) {
+	if w.opts.OnError != nil {
+		w.opts.OnError(err, envName)
+		return
+	}
+	OnErrorLogAndContinue(err, rawVal, target, envName, flagName)
+}
@@ -0,0 +1,219 @@
+package enflag
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+	defer func() { EnvFileSuffix = "" }()
+
+	dir := t.TempDir()
+	mtime := time.Now()
+	write := func(name string, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		// the modification time is moved forward explicitly,
+		// as its resolution may be too coarse for the test
+		mtime = mtime.Add(time.Second)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	secrets := filepath.Join(dir, "secrets")
+	if err := os.Mkdir(secrets, 0700); err != nil {
+		t.Fatal(err)
+	}
+	write(filepath.Join("secrets", "WATCH_USER"), "guest")
+	if err := LoadDir(secrets); err != nil {
+		t.Fatal(err)
+	}
+
+	EnvFileSuffix = "_FILE"
+	os.Setenv("WATCH_WORKERS", write("workers", "8"))
+	os.Setenv("WATCH_PASSWORD_FILE", write("password", "qwerty"))
+	os.Setenv("WATCH_FLAG", write("flag", "1"))
+	os.Setenv("WATCH_HOSTS", write("hosts", "a,b"))
+	os.Args = []string{"cmd", "-watch-flag", write("flag-arg", "2")}
+	defer os.Unsetenv("WATCH_WORKERS")
+	defer os.Unsetenv("WATCH_PASSWORD_FILE")
+	defer os.Unsetenv("WATCH_FLAG")
+	defer os.Unsetenv("WATCH_HOSTS")
+
+	var workers, fl int
+	var password, user string
+	Var(&workers).FromFile().BindEnv("WATCH_WORKERS")
+	Var(&password).BindEnv("WATCH_PASSWORD")
+	Var(&user).BindEnv("WATCH_USER")
+	Var(&fl).FromFile().Bind("WATCH_FLAG", "watch-flag")
+	var hosts []string
+	Var(&hosts).WithDefault([]string{"localhost"}).FromFile().BindEnv("WATCH_HOSTS")
+	Parse()
+
+	checkVal(t, 8, workers)
+	checkVal(t, "qwerty", password)
+	checkVal(t, "guest", user)
+	checkVal(t, 2, fl)
+	checkSlice(t, []string{"localhost", "a", "b"}, hosts)
+
+	reloaded := make(chan []string, 10)
+	failed := make(chan string, 10)
+	stop := Watch(WatchOptions{
+		Interval: 5 * time.Millisecond,
+		Debounce: 20 * time.Millisecond,
+		OnReload: func(names []string) {
+			sort.Strings(names)
+			reloaded <- names
+		},
+		OnError: func(err error, envName string) {
+			failed <- envName
+		},
+	})
+	defer stop()
+
+	wait := func() string {
+		t.Helper()
+
+		select {
+		case names := <-reloaded:
+			return strings.Join(names, ",")
+		case envName := <-failed:
+			return "error:" + envName
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+			return ""
+		}
+	}
+
+	write("workers", "16")
+	write("password", "secret")
+	write(filepath.Join("secrets", "WATCH_USER"), "admin")
+	write("flag", "3")
+	write("hosts", "c")
+	checkVal(t, "WATCH_HOSTS,WATCH_PASSWORD,WATCH_USER,WATCH_WORKERS", wait())
+	checkSlice(t, []string{"localhost", "c"}, hosts)
+	checkVal(t, 16, workers)
+	checkVal(t, "secret", password)
+	checkVal(t, "admin", user)
+	checkVal(t, 2, fl)
+
+	write("workers", "many")
+	checkVal(t, "error:WATCH_WORKERS", wait())
+	checkVal(t, 16, workers)
+
+	stop()
+	write("password", "changed")
+	time.Sleep(50 * time.Millisecond)
+	checkVal(t, "secret", password)
+}
+
+func TestWatchExpandHome(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, "token")
+	write := func(data string, mtime time.Time) {
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("one", time.Now())
+	t.Setenv("WATCH_HOME_TOKEN", "~/token")
+
+	var token string
+	Var(&token).ExpandHome().FromFile().BindEnv("WATCH_HOME_TOKEN")
+	Parse()
+	checkVal(t, "one", token)
+
+	reloaded := make(chan []string, 10)
+	stop := Watch(WatchOptions{
+		Interval: 5 * time.Millisecond,
+		OnReload: func(names []string) { reloaded <- names },
+	})
+	defer stop()
+
+	write("two", time.Now().Add(time.Second))
+	select {
+	case names := <-reloaded:
+		checkSlice(t, []string{"WATCH_HOME_TOKEN"}, names)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	checkVal(t, "two", token)
+}
+
+func TestWatchEnvFile(t *testing.T) {
+	ErrorHandlerFunc = OnErrorIgnore
+	reset()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	mtime := time.Now()
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		mtime = mtime.Add(time.Second)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("WATCH_ENV_PORT=8080\nWATCH_ENV_HOST=a\n")
+	if err := LoadEnvFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	var port int
+	var host string
+	Var(&port).BindEnv("WATCH_ENV_PORT")
+	Var(&host).BindEnv("WATCH_ENV_HOST")
+	Parse()
+	checkVal(t, 8080, port)
+
+	reloaded := make(chan []string, 10)
+	errs := make(chan error, 10)
+	stop := Watch(WatchOptions{
+		Interval: 5 * time.Millisecond,
+		OnReload: func(names []string) { reloaded <- names },
+		OnError:  func(err error, envName string) { errs <- err },
+	})
+	defer stop()
+
+	write("WATCH_ENV_PORT=9090\nWATCH_ENV_HOST=a\n")
+	select {
+	case names := <-reloaded:
+		checkSlice(t, []string{"WATCH_ENV_PORT"}, names)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	checkVal(t, 9090, port)
+
+	// an invalid file is reported and the previous values are kept
+	write("WATCH_ENV_PORT\n")
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "invalid line") {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	stop()
+	checkVal(t, 9090, port)
+	checkVal(t, "a", host)
+}